@@ -0,0 +1,20 @@
+package echoadapter
+
+import (
+	"github.com/krisalay/error-framework/alerting"
+	"github.com/labstack/echo/v4"
+)
+
+// AlertingMiddleware counts every request for the engine's ratio rules
+func AlertingMiddleware(engine *alerting.Engine) echo.MiddlewareFunc {
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+
+		return func(c echo.Context) error {
+
+			engine.RecordRequest()
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package echoadapter
+
+import (
+	"encoding/json"
+
+	"github.com/krisalay/error-framework/core"
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderWarnings carries the collected warnings of a successful response as a JSON array
+const HeaderWarnings = "X-Warnings"
+
+// CollectorMiddleware gives each request a core.Collector for soft errors and warnings.
+// Collected entries are logged through the manager when the request ends.
+// Successful responses carry the warnings in the X-Warnings header;
+// error responses include them in the body when the Handler enables WithWarnings.
+func CollectorMiddleware(manager *core.Manager) echo.MiddlewareFunc {
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+
+		return func(c echo.Context) error {
+
+			req := c.Request()
+
+			collector := core.NewCollector()
+			ctx := core.WithCollector(req.Context(), collector)
+
+			c.SetRequest(req.WithContext(ctx))
+
+			res := c.Response()
+
+			res.Before(func() {
+
+				if res.Status < 200 || res.Status >= 300 {
+					return
+				}
+
+				setWarningsHeader(c, collector.Warnings())
+			})
+
+			// Warnings stay on the collector after flushing, so the error handler can still render them
+			defer manager.FlushCollected(ctx)
+
+			return next(c)
+		}
+	}
+}
+
+// Warnings returns the warnings collected so far for the request
+func Warnings(c echo.Context) []core.Warning {
+
+	collector := core.CollectorFromContext(c.Request().Context())
+
+	if collector == nil {
+		return nil
+	}
+
+	return collector.Warnings()
+}
+
+func setWarningsHeader(c echo.Context, warnings []core.Warning) {
+
+	if len(warnings) == 0 {
+		return
+	}
+
+	encoded, err := json.Marshal(warnings)
+
+	if err != nil {
+		return
+	}
+
+	c.Response().Header().Set(HeaderWarnings, string(encoded))
+}
@@ -0,0 +1,42 @@
+package echoadapter
+
+import (
+	"github.com/krisalay/error-framework/core"
+	"github.com/labstack/echo/v4"
+)
+
+// DebugMiddleware passes the signed debug header and the client IP to the manager's debug policy.
+// The token only takes effect in staging; production never exposes internals.
+// The client IP is the connection's remote address unless Echo's IPExtractor is set,
+// so forwarding headers are only trusted once a proxy-aware extractor is configured.
+func DebugMiddleware() echo.MiddlewareFunc {
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+
+		return func(c echo.Context) error {
+
+			token := c.Request().Header.Get(core.HeaderDebugToken)
+
+			if token != "" {
+				req := c.Request()
+
+				ctx := core.WithDebugToken(req.Context(), token)
+				ctx = core.WithDebugClient(ctx, clientIP(c))
+
+				c.SetRequest(req.WithContext(ctx))
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// clientIP ignores X-Forwarded-For and X-Real-IP, which any client can set, unless an extractor is configured
+func clientIP(c echo.Context) string {
+
+	if c.Echo().IPExtractor != nil {
+		return c.RealIP()
+	}
+
+	return echo.ExtractIPDirect()(c.Request())
+}
@@ -0,0 +1,58 @@
+package echoadapter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	echoadapter "github.com/krisalay/error-framework/adapters/echo"
+	"github.com/krisalay/error-framework/core"
+	"github.com/krisalay/error-framework/errtest"
+	"github.com/labstack/echo/v4"
+)
+
+func TestDebugMiddleware_IgnoresForwardedFor(t *testing.T) {
+
+	policy, err := core.NewDebugPolicy(core.EnvStaging, "secret")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy.WithAllowedNetworks(netip.MustParsePrefix("10.0.0.0/8"))
+
+	var allowed bool
+
+	e := echo.New()
+	e.Use(echoadapter.DebugMiddleware())
+
+	e.GET("/", func(c echo.Context) error {
+		allowed = policy.Allowed(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	request := func(remoteAddr string) *http.Request {
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(core.HeaderDebugToken, core.SignDebugToken("secret", time.Now()))
+		req.Header.Set(echo.HeaderXForwardedFor, "10.0.0.1")
+		req.Header.Set(echo.HeaderXRealIP, "10.0.0.1")
+
+		return req
+	}
+
+	errtest.Serve(e, request("203.0.113.7:4711"))
+
+	if allowed {
+		t.Fatal("Spoofed X-Forwarded-For accepted without a trusted IP extractor")
+	}
+
+	errtest.Serve(e, request("10.1.2.3:4711"))
+
+	if !allowed {
+		t.Fatal("Token rejected from an allowed remote address")
+	}
+}
@@ -1,12 +1,15 @@
 package echoadapter
 
 import (
+	"fmt"
+
 	"github.com/krisalay/error-framework/core"
 	"github.com/labstack/echo/v4"
 )
 
 type Handler struct {
-	manager *core.Manager
+	manager         *core.Manager
+	includeWarnings bool
 }
 
 func NewHandler(manager *core.Manager) *Handler {
@@ -15,22 +18,28 @@ func NewHandler(manager *core.Manager) *Handler {
 	}
 }
 
+// WithWarnings includes collected warnings in error responses
+func (h *Handler) WithWarnings(enabled bool) *Handler {
+	h.includeWarnings = enabled
+	return h
+}
+
 func (h *Handler) Handle(err error, c echo.Context) {
 
 	ctx := c.Request().Context()
 
 	appErr := h.manager.Handle(ctx, err)
 
-	response := map[string]any{
-		"message":  appErr.SafeMessage(),
-		"code":     appErr.SafeCode(),
-		"status":   appErr.Status,
-		"trace_id": appErr.TraceID,
+	var warnings []core.Warning
+
+	if h.includeWarnings {
+		warnings = Warnings(c)
 	}
 
-	// include details only if not sensitive
-	if !appErr.IsSensitive && len(appErr.Details) > 0 {
-		response["details"] = appErr.Details
+	response := h.manager.Render(ctx, appErr, warnings)
+
+	if retryAfter, ok := appErr.Details[core.DetailRetryAfter]; ok {
+		c.Response().Header().Set("Retry-After", fmt.Sprint(retryAfter))
 	}
 
 	if !c.Response().Committed {
@@ -0,0 +1,67 @@
+package echoadapter_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/krisalay/error-framework/core"
+	"github.com/krisalay/error-framework/errtest"
+	"github.com/labstack/echo/v4"
+)
+
+func TestHandler_MatchesRespond(t *testing.T) {
+
+	manager := core.NewManager(core.ManagerConfig{
+		Logger: errtest.NewRecorder(),
+		Response: core.ResponseConfig{
+			Naming:   core.NamingCamel,
+			Envelope: "error",
+		},
+	})
+
+	newErr := func() error {
+		return core.New().
+			WithMessage("email is required").
+			WithCode(core.CodeValidationError).
+			WithStatus(http.StatusBadRequest).
+			WithSensitive(false).
+			WithDetail("email", "is required").
+			Build()
+	}
+
+	e := errtest.NewEcho(manager)
+
+	e.GET("/", func(c echo.Context) error {
+		return newErr()
+	})
+
+	rec := errtest.Serve(e, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	status, body := manager.Respond(context.Background(), newErr())
+
+	expected, err := json.Marshal(body)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != status {
+		t.Fatalf("Expected status %d, got %d", status, rec.Code)
+	}
+
+	if string(expected) != strings.TrimSpace(rec.Body.String()) {
+		t.Fatalf("Echo handler and Respond differ:\n%s\n%s", expected, rec.Body.String())
+	}
+
+	var decoded map[string]map[string]any
+
+	json.Unmarshal(expected, &decoded)
+
+	if _, ok := decoded["error"]["traceId"]; !ok {
+		t.Fatalf("Naming or envelope not applied: %s", expected)
+	}
+}
@@ -0,0 +1,29 @@
+package echoadapter
+
+import (
+	"github.com/krisalay/error-framework/metrics"
+	"github.com/labstack/echo/v4"
+)
+
+// MetricsMiddleware records how many errors each request produced.
+// The returned error is passed on unchanged and counted if the HTTP error handler has not handled it yet.
+func MetricsMiddleware(collector *metrics.Collector) echo.MiddlewareFunc {
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+
+		return func(c echo.Context) error {
+
+			req := c.Request()
+
+			ctx, done := collector.Track(req.Context())
+
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+
+			done(err)
+
+			return err
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/krisalay/error-framework/core"
+	"github.com/krisalay/error-framework/utils"
 	"github.com/labstack/echo/v4"
 )
 
@@ -19,14 +20,9 @@ func PanicMiddleware(manager *core.Manager) echo.MiddlewareFunc {
 
 					ctx := c.Request().Context()
 
-					appErr := manager.HandlePanic(ctx, recovered)
+					appErr := manager.HandlePanicWithStack(ctx, recovered, utils.CapturePanic())
 
-					response := map[string]any{
-						"message":  appErr.SafeMessage(),
-						"code":     appErr.SafeCode(),
-						"status":   http.StatusInternalServerError,
-						"trace_id": appErr.TraceID,
-					}
+					response := manager.Render(ctx, appErr, nil)
 
 					c.JSON(http.StatusInternalServerError, response)
 				}
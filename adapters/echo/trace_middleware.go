@@ -1,17 +1,20 @@
 package echoadapter
 
 import (
-	"github.com/google/uuid"
 	"github.com/krisalay/error-framework/utils"
 	"github.com/labstack/echo/v4"
 )
 
 const (
-	HeaderTraceID     = "X-Trace-ID"
-	HeaderRequestID   = "X-Request-ID"
-	HeaderTraceParent = "traceparent"
+	HeaderTraceID     = utils.HeaderTraceID
+	HeaderRequestID   = utils.HeaderRequestID
+	HeaderTraceParent = utils.HeaderTraceParent
+	HeaderTraceState  = utils.HeaderTraceState
 )
 
+// TraceMiddleware continues the caller's trace, or starts one, with a new span for this request.
+// Headers are read and echoed on the response with traceProvider's propagator, plus X-Trace-ID.
+// Logs and X-Trace-ID keep the trace ID in its native form; traceparent and b3 carry it as hex.
 func TraceMiddleware(traceProvider *utils.TraceProvider) echo.MiddlewareFunc {
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
@@ -20,38 +23,34 @@ func TraceMiddleware(traceProvider *utils.TraceProvider) echo.MiddlewareFunc {
 
 			req := c.Request()
 
-			traceID := extractTraceID(c)
+			sc, ok := traceProvider.Extract(req.Header)
 
-			ctx := traceProvider.Inject(req.Context(), traceID)
+			if !ok {
+				sc = utils.SpanContext{
+					TraceID:    traceProvider.NewTraceID(),
+					TraceFlags: utils.TraceFlagSampled,
+				}
+			}
 
-			c.SetRequest(req.WithContext(ctx))
-
-			c.Response().Header().Set(HeaderTraceID, traceID)
-
-			return next(c)
-		}
-	}
-}
+			sc.SpanID = utils.NewSpanID()
 
-func extractTraceID(c echo.Context) string {
+			ctx := traceProvider.InjectSpanContext(req.Context(), sc)
 
-	traceID := c.Request().Header.Get(HeaderTraceID)
+			c.SetRequest(req.WithContext(ctx))
 
-	if traceID != "" {
-		return traceID
-	}
+			header := c.Response().Header()
 
-	traceID = c.Request().Header.Get(HeaderRequestID)
+			propagated := sc
 
-	if traceID != "" {
-		return traceID
-	}
+			// A request ID that has no hex form still gets a traceparent, under a fresh W3C trace ID
+			if _, ok := utils.W3CTraceID(sc.TraceID); !ok {
+				propagated.TraceID = utils.W3CGenerator{}.NewTraceID()
+			}
 
-	traceID = c.Request().Header.Get(HeaderTraceParent)
+			traceProvider.Propagator().Inject(propagated, header)
+			header.Set(HeaderTraceID, sc.TraceID)
 
-	if traceID != "" {
-		return traceID
+			return next(c)
+		}
 	}
-
-	return uuid.New().String()
 }
@@ -0,0 +1,97 @@
+package echoadapter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	echoadapter "github.com/krisalay/error-framework/adapters/echo"
+	"github.com/krisalay/error-framework/errtest"
+	"github.com/krisalay/error-framework/utils"
+	"github.com/labstack/echo/v4"
+)
+
+func TestTraceMiddleware_SameTraceIDEverywhere(t *testing.T) {
+
+	traceProvider := utils.NewTraceProvider()
+
+	var logged string
+
+	e := echo.New()
+	e.Use(echoadapter.TraceMiddleware(traceProvider))
+
+	e.GET("/", func(c echo.Context) error {
+		logged = traceProvider.GetTraceID(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	rec := errtest.Serve(e, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	sc, err := utils.ParseTraceParent(rec.Header().Get(utils.HeaderTraceParent))
+
+	if err != nil {
+		t.Fatalf("No traceparent in response: %v", err)
+	}
+
+	traceID := rec.Header().Get(utils.HeaderTraceID)
+	hexID, _ := utils.W3CTraceID(traceID)
+
+	if traceID != logged || hexID != sc.TraceID {
+		t.Fatalf("Trace IDs differ: X-Trace-ID=%s traceparent=%s context=%s", traceID, sc.TraceID, logged)
+	}
+}
+
+func TestTraceMiddleware_KeepsNativeFormat(t *testing.T) {
+
+	traceProvider := utils.NewTraceProvider().WithGenerator(utils.ULIDGenerator{})
+
+	var logged string
+
+	e := echo.New()
+	e.Use(echoadapter.TraceMiddleware(traceProvider))
+
+	e.GET("/", func(c echo.Context) error {
+		logged = traceProvider.GetTraceID(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	rec := errtest.Serve(e, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if len(logged) != 26 || rec.Header().Get(utils.HeaderTraceID) != logged {
+		t.Fatalf("ULID not kept for logs and X-Trace-ID: context=%s X-Trace-ID=%s",
+			logged, rec.Header().Get(utils.HeaderTraceID))
+	}
+
+	sc, err := utils.ParseTraceParent(rec.Header().Get(utils.HeaderTraceParent))
+
+	if err != nil {
+		t.Fatalf("No traceparent in response: %v", err)
+	}
+
+	if hexID, _ := utils.W3CTraceID(logged); hexID != sc.TraceID {
+		t.Fatalf("traceparent does not carry the ULID: %s", sc.TraceID)
+	}
+}
+
+func TestTraceMiddleware_NonHexRequestIDGetsTraceParent(t *testing.T) {
+
+	e := echo.New()
+	e.Use(echoadapter.TraceMiddleware(utils.NewTraceProvider()))
+
+	e.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(utils.HeaderRequestID, "req-123")
+
+	rec := errtest.Serve(e, req)
+
+	if rec.Header().Get(utils.HeaderTraceID) != "req-123" {
+		t.Fatalf("Request ID not kept: %s", rec.Header().Get(utils.HeaderTraceID))
+	}
+
+	if _, err := utils.ParseTraceParent(rec.Header().Get(utils.HeaderTraceParent)); err != nil {
+		t.Fatalf("No traceparent for a non-hex request ID: %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/krisalay/error-framework/core"
+)
+
+const (
+	ContentTypeProblemJSON = "application/problem+json"
+
+	maxErrorBodySize = 64 << 10
+)
+
+// RemoteError is the error reported by a downstream service
+type RemoteError struct {
+	Status  int
+	Code    string // the downstream code, or the problem type for problem+json
+	Message string
+	TraceID string
+	Details map[string]any
+}
+
+func (e *RemoteError) Error() string {
+
+	if e.Code == "" {
+		return fmt.Sprintf("downstream status %d: %s", e.Status, e.Message)
+	}
+
+	return fmt.Sprintf("downstream %s (status %d): %s", e.Code, e.Status, e.Message)
+}
+
+// Decoder turns non-2xx downstream responses into AppErrors
+type Decoder struct {
+	statuses      map[int]int
+	defaultStatus int
+}
+
+// NewDecoder maps every downstream error status to 502 Bad Gateway until configured otherwise
+func NewDecoder() *Decoder {
+	return &Decoder{
+		statuses:      make(map[int]int),
+		defaultStatus: http.StatusBadGateway,
+	}
+}
+
+// WithStatus maps a downstream status to the local status of the AppError
+func (d *Decoder) WithStatus(remote, local int) *Decoder {
+	d.statuses[remote] = local
+	return d
+}
+
+// WithDefaultStatus sets the local status for downstream statuses without a mapping
+func (d *Decoder) WithDefaultStatus(local int) *Decoder {
+	d.defaultStatus = local
+	return d
+}
+
+// FromResponse returns nil for statuses below 400.
+// Otherwise it reads the body, which the caller still closes, and returns an
+// AppError caused by a *RemoteError.
+func (d *Decoder) FromResponse(resp *http.Response) *core.AppError {
+
+	if resp == nil || resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+
+	remote := decodeRemoteError(resp)
+
+	status, ok := d.statuses[resp.StatusCode]
+
+	if !ok {
+		status = d.defaultStatus
+	}
+
+	// Level and sensitivity both follow the local status the client sees
+	serverError := status >= http.StatusInternalServerError
+
+	level := core.LevelWarn
+
+	if serverError {
+		level = core.LevelError
+	}
+
+	builder := core.New().
+		WithMessage("Downstream service error").
+		WithCode(core.CodeDownstreamError).
+		WithStatus(status).
+		WithLevel(level).
+		WithInternal(remote).
+		WithSensitive(serverError)
+
+	if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		builder = builder.WithDetail(core.DetailRetryAfter, retryAfter)
+	}
+
+	return builder.Build()
+}
+
+// parseRetryAfter returns the seconds to wait, from delay-seconds or an HTTP-date
+func parseRetryAfter(value string, now time.Time) (int, bool) {
+
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(seconds, 0), true
+	}
+
+	at, err := http.ParseTime(value)
+
+	if err != nil {
+		return 0, false
+	}
+
+	return max(int(at.Sub(now).Round(time.Second).Seconds()), 0), true
+}
+
+func decodeRemoteError(resp *http.Response) *RemoteError {
+
+	remote := &RemoteError{
+		Status:  resp.StatusCode,
+		Message: http.StatusText(resp.StatusCode),
+	}
+
+	if resp.Body == nil {
+		return remote
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+	if err != nil || len(body) == 0 {
+		return remote
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+
+	var fields map[string]any
+
+	if json.Unmarshal(body, &fields) != nil {
+
+		if mediaType == "text/plain" {
+			remote.Message = strings.TrimSpace(string(body))
+		}
+
+		return remote
+	}
+
+	if mediaType == ContentTypeProblemJSON || isProblem(fields) {
+		decodeProblem(remote, fields)
+	} else {
+		decodeAppError(remote, core.UnwrapEnvelope(fields))
+	}
+
+	return remote
+}
+
+// isProblem recognizes problem details sent with a generic JSON content type
+func isProblem(fields map[string]any) bool {
+
+	if _, ok := fields[core.FieldMessage]; ok {
+		return false
+	}
+
+	return stringField(fields, "type", "title", "detail") != ""
+}
+
+// decodeProblem reads RFC 9457 problem details
+func decodeProblem(remote *RemoteError, fields map[string]any) {
+
+	remote.Code = stringField(fields, "type")
+
+	// Many services add a code extension member
+	if code := stringField(fields, "code"); code != "" {
+		remote.Code = code
+	}
+
+	if detail := stringField(fields, "detail"); detail != "" {
+		remote.Message = detail
+	} else if title := stringField(fields, "title"); title != "" {
+		remote.Message = title
+	}
+
+	remote.TraceID = stringField(fields, "trace_id", "traceId")
+}
+
+// decodeAppError reads this framework's response format in either field naming
+func decodeAppError(remote *RemoteError, fields map[string]any) {
+
+	remote.Code = stringField(fields, core.FieldCode)
+
+	if message := stringField(fields, core.FieldMessage); message != "" {
+		remote.Message = message
+	}
+
+	remote.TraceID = stringField(fields, core.FieldTraceID, "traceId")
+
+	if details, ok := fields[core.FieldDetails].(map[string]any); ok {
+		remote.Details = details
+	}
+}
+
+func stringField(fields map[string]any, names ...string) string {
+
+	for _, name := range names {
+		if value, ok := fields[name].(string); ok && value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
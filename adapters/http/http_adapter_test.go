@@ -0,0 +1,183 @@
+package httpadapter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krisalay/error-framework/core"
+	"github.com/krisalay/error-framework/utils"
+)
+
+func TestTransport_InjectsTraceHeaders(t *testing.T) {
+
+	var received http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	provider := utils.NewTraceProvider()
+
+	ctx := provider.InjectSpanContext(context.Background(), utils.SpanContext{
+		TraceID:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:     "00f067aa0ba902b7",
+		TraceFlags: utils.TraceFlagSampled,
+	})
+
+	client := &http.Client{Transport: NewTransport(nil, provider)}
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+
+	resp, err := client.Do(req)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if received.Get(utils.HeaderTraceParent) != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Fatal("traceparent not forwarded", received)
+	}
+
+	if req.Header.Get(utils.HeaderTraceParent) != "" {
+		t.Fatal("Caller's request modified")
+	}
+}
+
+func response(status int, contentType, body string) *http.Response {
+
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": {contentType}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestDecoder_FrameworkError(t *testing.T) {
+
+	appErr := NewDecoder().FromResponse(response(
+		http.StatusNotFound,
+		"application/json",
+		`{"error":{"code":"NOT_FOUND","message":"Order not found","trace_id":"remote-trace","details":{"order_id":"o-1"}}}`,
+	))
+
+	if appErr.Code != core.CodeDownstreamError || appErr.Status != http.StatusBadGateway {
+		t.Fatal("Unexpected error", appErr.Code, appErr.Status)
+	}
+
+	var remote *RemoteError
+
+	if !errors.As(appErr, &remote) {
+		t.Fatal("Remote error not kept as cause")
+	}
+
+	if remote.Code != core.CodeNotFound || remote.Message != "Order not found" || remote.TraceID != "remote-trace" || remote.Details["order_id"] != "o-1" {
+		t.Fatalf("Unexpected remote error %+v", remote)
+	}
+}
+
+func TestDecoder_ProblemJSON(t *testing.T) {
+
+	decoder := NewDecoder().WithStatus(http.StatusConflict, http.StatusConflict)
+
+	appErr := decoder.FromResponse(response(
+		http.StatusConflict,
+		ContentTypeProblemJSON+"; charset=utf-8",
+		`{"type":"https://example.com/probs/out-of-stock","title":"Out of stock","detail":"Item 42 is out of stock","status":409}`,
+	))
+
+	if appErr.Status != http.StatusConflict || appErr.IsSensitive || appErr.Level != core.LevelWarn {
+		t.Fatal("Status mapping not applied", appErr.Status, appErr.IsSensitive, appErr.Level)
+	}
+
+	var remote *RemoteError
+
+	errors.As(appErr, &remote)
+
+	if remote.Code != "https://example.com/probs/out-of-stock" || remote.Message != "Item 42 is out of stock" {
+		t.Fatalf("Unexpected remote error %+v", remote)
+	}
+}
+
+func TestDecoder_RetryAfterAndSuccess(t *testing.T) {
+
+	resp := response(http.StatusServiceUnavailable, "text/html", "<html>down</html>")
+	resp.Header.Set("Retry-After", "30")
+
+	appErr := NewDecoder().FromResponse(resp)
+
+	if appErr.Details[core.DetailRetryAfter] != 30 {
+		t.Fatal("Retry-After not kept", appErr.Details)
+	}
+
+	if NewDecoder().FromResponse(response(http.StatusOK, "application/json", `{}`)) != nil {
+		t.Fatal("Success decoded as error")
+	}
+}
+
+func TestDecoder_LevelFollowsLocalStatus(t *testing.T) {
+
+	cases := map[string]struct {
+		decoder   *Decoder
+		remote    int
+		level     core.ErrorLevel
+		sensitive bool
+	}{
+		"client error mapped to 502": {NewDecoder(), http.StatusNotFound, core.LevelError, true},
+		"client error kept":          {NewDecoder().WithStatus(http.StatusNotFound, http.StatusNotFound), http.StatusNotFound, core.LevelWarn, false},
+		"server error mapped to 409": {NewDecoder().WithDefaultStatus(http.StatusConflict), http.StatusInternalServerError, core.LevelWarn, false},
+	}
+
+	for name, c := range cases {
+
+		appErr := c.decoder.FromResponse(response(c.remote, "text/plain", "failed"))
+
+		if appErr.Level != c.level || appErr.IsSensitive != c.sensitive {
+			t.Errorf("%s: got level %s, sensitive %v", name, appErr.Level, appErr.IsSensitive)
+		}
+	}
+}
+
+func TestDecoder_ProblemShapeWithJSONContentType(t *testing.T) {
+
+	appErr := NewDecoder().FromResponse(response(
+		http.StatusConflict,
+		"application/json",
+		`{"type":"about:blank","title":"Conflict","detail":"Version mismatch"}`,
+	))
+
+	var remote *RemoteError
+
+	errors.As(appErr, &remote)
+
+	if remote.Code != "about:blank" || remote.Message != "Version mismatch" {
+		t.Fatalf("Problem details not recognized: %+v", remote)
+	}
+}
+
+func TestParseRetryAfter_HTTPDate(t *testing.T) {
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	seconds, ok := parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now)
+
+	if !ok || seconds != 90 {
+		t.Fatalf("Unexpected retry after: %d, %v", seconds, ok)
+	}
+
+	if seconds, ok := parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now); !ok || seconds != 0 {
+		t.Fatalf("Past dates should mean retry now, got %d", seconds)
+	}
+
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Fatal("Malformed Retry-After accepted")
+	}
+}
@@ -0,0 +1,41 @@
+package httpadapter
+
+import (
+	"net/http"
+
+	"github.com/krisalay/error-framework/utils"
+)
+
+// Transport is an http.RoundTripper that forwards the request context's trace
+type Transport struct {
+	base          http.RoundTripper
+	traceProvider *utils.TraceProvider
+}
+
+// NewTransport wraps base, or http.DefaultTransport when nil.
+// Headers are written with traceProvider's propagator.
+func NewTransport(base http.RoundTripper, traceProvider *utils.TraceProvider) *Transport {
+
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if traceProvider == nil {
+		traceProvider = utils.NewTraceProvider()
+	}
+
+	return &Transport{
+		base:          base,
+		traceProvider: traceProvider,
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	// RoundTrippers must not modify the caller's request
+	outbound := req.Clone(req.Context())
+
+	t.traceProvider.InjectHeaders(req.Context(), outbound.Header)
+
+	return t.base.RoundTrip(outbound)
+}
@@ -0,0 +1,107 @@
+package oteladapter
+
+import (
+	"context"
+
+	"github.com/krisalay/error-framework/core"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const EventName = "app_error"
+
+const (
+	AttrCode    = attribute.Key("app_error.code")
+	AttrStatus  = attribute.Key("app_error.status")
+	AttrLevel   = attribute.Key("app_error.level")
+	AttrDomain  = attribute.Key("app_error.domain")
+	AttrMessage = attribute.Key("app_error.message")
+)
+
+// TraceProvider reads trace and span IDs from the active OpenTelemetry span
+type TraceProvider struct {
+	fallback core.TraceProvider
+}
+
+func NewTraceProvider() *TraceProvider {
+	return &TraceProvider{}
+}
+
+// WithFallback sets the provider used when the context carries no span
+func (p *TraceProvider) WithFallback(fallback core.TraceProvider) *TraceProvider {
+	p.fallback = fallback
+	return p
+}
+
+func (p *TraceProvider) GetTraceID(ctx context.Context) string {
+
+	spanContext := trace.SpanContextFromContext(ctx)
+
+	if spanContext.HasTraceID() {
+		return spanContext.TraceID().String()
+	}
+
+	if p.fallback != nil {
+		return p.fallback.GetTraceID(ctx)
+	}
+
+	return ""
+}
+
+func (p *TraceProvider) GetSpanID(ctx context.Context) string {
+
+	spanContext := trace.SpanContextFromContext(ctx)
+
+	if spanContext.HasSpanID() {
+		return spanContext.SpanID().String()
+	}
+
+	if spanProvider, ok := p.fallback.(core.SpanProvider); ok {
+		return spanProvider.GetSpanID(ctx)
+	}
+
+	return ""
+}
+
+// SpanRecorder records handled errors as events on the active span.
+// It implements core.Observer.
+type SpanRecorder struct{}
+
+func NewSpanRecorder() *SpanRecorder {
+	return &SpanRecorder{}
+}
+
+func (r *SpanRecorder) Observe(ctx context.Context, err *core.AppError) {
+
+	if err == nil {
+		return
+	}
+
+	span := trace.SpanFromContext(ctx)
+
+	if !span.IsRecording() {
+		return
+	}
+
+	options := []trace.EventOption{
+		trace.WithAttributes(
+			AttrCode.String(err.Code),
+			AttrStatus.Int(err.Status),
+			AttrLevel.String(err.Level.String()),
+			AttrDomain.String(err.Domain),
+			AttrMessage.String(err.SafeMessage()),
+		),
+	}
+
+	if !err.Timestamp.IsZero() {
+		options = append(options, trace.WithTimestamp(err.Timestamp))
+	}
+
+	span.AddEvent(EventName, options...)
+
+	// Client errors are expected outcomes, only server errors fail the span
+	if err.Status >= 500 {
+		span.SetStatus(codes.Error, err.Code)
+	}
+}
@@ -0,0 +1,93 @@
+package oteladapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krisalay/error-framework/core"
+	"github.com/krisalay/error-framework/errtest"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newManager() *core.Manager {
+	return core.NewManager(core.ManagerConfig{
+		Logger:        errtest.NewRecorder(),
+		TraceProvider: NewTraceProvider(),
+		Observers:     []core.Observer{NewSpanRecorder()},
+	})
+}
+
+func TestSpanRecorder_ServerError(t *testing.T) {
+
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)).Tracer("test")
+
+	ctx, span := tracer.Start(context.Background(), "request")
+
+	appErr := newManager().Handle(ctx, core.New().WithCode(core.CodeDBError).Build())
+
+	span.End()
+
+	spans := exporter.GetSpans()
+
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+
+	if appErr.TraceID != spans[0].SpanContext.TraceID().String() {
+		t.Fatal("Trace ID not taken from span")
+	}
+
+	if appErr.SpanID != spans[0].SpanContext.SpanID().String() {
+		t.Fatal("Span ID not taken from span")
+	}
+
+	if len(spans[0].Events) != 1 || spans[0].Events[0].Name != EventName {
+		t.Fatal("Error event not recorded")
+	}
+
+	if spans[0].Status.Code != codes.Error {
+		t.Fatal("Span status not set to error")
+	}
+}
+
+func TestSpanRecorder_ClientErrorKeepsStatus(t *testing.T) {
+
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)).Tracer("test")
+
+	ctx, span := tracer.Start(context.Background(), "request")
+
+	newManager().Handle(ctx, core.New().WithCode(core.CodeNotFound).WithStatus(404).Build())
+
+	span.End()
+
+	spans := exporter.GetSpans()
+
+	if len(spans[0].Events) != 1 {
+		t.Fatal("Error event not recorded")
+	}
+
+	if spans[0].Status.Code == codes.Error {
+		t.Fatal("4xx should not set span status to error")
+	}
+}
+
+func TestTraceProvider_Fallback(t *testing.T) {
+
+	provider := NewTraceProvider().WithFallback(&staticTraceProvider{id: "fallback"})
+
+	if provider.GetTraceID(context.Background()) != "fallback" {
+		t.Fatal("Fallback not used without span")
+	}
+}
+
+type staticTraceProvider struct {
+	id string
+}
+
+func (s *staticTraceProvider) GetTraceID(ctx context.Context) string {
+	return s.id
+}
@@ -0,0 +1,278 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/krisalay/error-framework/core"
+)
+
+type Alert struct {
+	Rule      string    `json:"rule"`
+	Message   string    `json:"message"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	Window    string    `json:"window"`
+	Code      string    `json:"code"`
+	TraceID   string    `json:"trace_id,omitempty"`
+	FiredAt   time.Time `json:"fired_at"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+type Config struct {
+	Rules    []Rule
+	Notifier Notifier
+
+	// Cooldown suppresses repeated alerts for the same rule, defaults to 5m
+	Cooldown time.Duration
+	// MaxPerMinute limits notifications across all rules, defaults to 10
+	MaxPerMinute int
+	// OnError receives delivery failures
+	OnError func(alert Alert, err error)
+
+	Now func() time.Time
+}
+
+type ruleState struct {
+	rule      Rule
+	errors    *slidingCounter
+	requests  *slidingCounter
+	lastFired time.Time
+}
+
+// Engine evaluates alert rules against handled errors.
+// It implements core.Observer.
+type Engine struct {
+	config Config
+
+	mu      sync.Mutex
+	rules   []*ruleState
+	sent    *slidingCounter
+	limited uint64
+	closed  bool
+
+	// ctx is canceled by Close to abort in-flight notifications
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewEngine(config Config) *Engine {
+
+	if config.Notifier == nil {
+		panic("Notifier is required for alerting engine")
+	}
+
+	if config.Cooldown <= 0 {
+		config.Cooldown = 5 * time.Minute
+	}
+
+	if config.MaxPerMinute <= 0 {
+		config.MaxPerMinute = 10
+	}
+
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	engine := &Engine{
+		config: config,
+		sent:   newSlidingCounter(time.Minute),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	for _, rule := range config.Rules {
+
+		if rule.Match == nil {
+			rule.Match = MatchAll()
+		}
+
+		if rule.Window <= 0 {
+			rule.Window = time.Minute
+		}
+
+		engine.rules = append(engine.rules, &ruleState{
+			rule:     rule,
+			errors:   newSlidingCounter(rule.Window),
+			requests: newSlidingCounter(rule.Window),
+		})
+	}
+
+	return engine
+}
+
+func (e *Engine) Observe(ctx context.Context, err *core.AppError) {
+
+	if err == nil {
+		return
+	}
+
+	now := e.config.Now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, state := range e.rules {
+
+		if !state.rule.Match(err) {
+			continue
+		}
+
+		state.errors.add(now)
+
+		value, firing := state.evaluate(now)
+
+		if !firing {
+			continue
+		}
+
+		// Deduplicate while the rule keeps firing
+		if !state.lastFired.IsZero() && now.Sub(state.lastFired) < e.config.Cooldown {
+			continue
+		}
+
+		if e.sent.sum(now) >= uint64(e.config.MaxPerMinute) {
+			e.limited++
+			continue
+		}
+
+		state.lastFired = now
+		e.sent.add(now)
+
+		e.dispatch(Alert{
+			Rule:      state.rule.Name,
+			Message:   state.message(value),
+			Value:     value,
+			Threshold: state.rule.Threshold,
+			Window:    state.rule.Window.String(),
+			Code:      err.Code,
+			TraceID:   err.TraceID,
+			FiredAt:   now,
+		})
+	}
+}
+
+// RecordRequest counts a request for ratio rules
+func (e *Engine) RecordRequest() {
+
+	now := e.config.Now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, state := range e.rules {
+		if state.rule.Ratio {
+			state.requests.add(now)
+		}
+	}
+}
+
+// Middleware records every request passing through next
+func (e *Engine) Middleware(next http.Handler) http.Handler {
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e.RecordRequest()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RateLimited returns the number of alerts suppressed by MaxPerMinute
+func (e *Engine) RateLimited() uint64 {
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.limited
+}
+
+// Wait blocks until in-flight notifications have been delivered or failed
+func (e *Engine) Wait() {
+	e.wg.Wait()
+}
+
+// Close stops sending alerts and waits for in-flight notifications.
+// When ctx is done first, they are canceled and Close returns ctx.Err().
+func (e *Engine) Close(ctx context.Context) error {
+
+	e.mu.Lock()
+	e.closed = true
+	e.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		e.wg.Wait()
+		close(done)
+	}()
+
+	defer e.cancel()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		e.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// dispatch is called with e.mu held
+func (e *Engine) dispatch(alert Alert) {
+
+	if e.closed {
+		return
+	}
+
+	e.wg.Add(1)
+
+	go func() {
+
+		defer e.wg.Done()
+
+		err := e.config.Notifier.Notify(e.ctx, alert)
+
+		if err != nil && e.config.OnError != nil {
+			e.config.OnError(alert, err)
+		}
+	}()
+}
+
+func (s *ruleState) evaluate(now time.Time) (float64, bool) {
+
+	count := float64(s.errors.sum(now))
+
+	if !s.rule.Ratio {
+		return count, count > s.rule.Threshold
+	}
+
+	requests := s.requests.sum(now)
+
+	if requests == 0 || requests < uint64(s.rule.MinRequests) {
+		return 0, false
+	}
+
+	ratio := count / float64(requests)
+
+	return ratio, ratio > s.rule.Threshold
+}
+
+func (s *ruleState) message(value float64) string {
+
+	if s.rule.Ratio {
+		return fmt.Sprintf("%s: error ratio %.2f%% above %.2f%% in %s",
+			s.rule.Name, value*100, s.rule.Threshold*100, s.rule.Window)
+	}
+
+	return fmt.Sprintf("%s: %.0f errors above %.0f in %s",
+		s.rule.Name, value, s.rule.Threshold, s.rule.Window)
+}
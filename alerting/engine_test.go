@@ -0,0 +1,243 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/krisalay/error-framework/core"
+)
+
+type webhookServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	alerts   []Alert
+	failures int
+}
+
+func newWebhookServer(failures int) *webhookServer {
+
+	ws := &webhookServer{failures: failures}
+
+	ws.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		ws.mu.Lock()
+		defer ws.mu.Unlock()
+
+		if ws.failures > 0 {
+			ws.failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var alert Alert
+		json.NewDecoder(r.Body).Decode(&alert)
+
+		ws.alerts = append(ws.alerts, alert)
+	}))
+
+	return ws
+}
+
+func (ws *webhookServer) received() []Alert {
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	return append([]Alert(nil), ws.alerts...)
+}
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func TestEngine_CountRuleDeduplicates(t *testing.T) {
+
+	server := newWebhookServer(0)
+	defer server.Close()
+
+	clk := &clock{now: time.Unix(1000, 0)}
+
+	engine := NewEngine(Config{
+		Rules: []Rule{
+			CountAbove("db-connection", MatchCode(core.CodeDBConnectionError), 2, time.Minute),
+		},
+		Notifier: NewWebhookNotifier(server.URL),
+		Now:      clk.Now,
+	})
+
+	for i := 0; i < 10; i++ {
+		engine.Observe(context.Background(), core.New().WithCode(core.CodeDBConnectionError).Build())
+	}
+
+	engine.Wait()
+
+	alerts := server.received()
+
+	if len(alerts) != 1 {
+		t.Fatalf("Expected 1 alert, got %d", len(alerts))
+	}
+
+	if alerts[0].Rule != "db-connection" || alerts[0].Value != 3 {
+		t.Fatalf("Unexpected alert: %+v", alerts[0])
+	}
+}
+
+func TestEngine_WindowSlides(t *testing.T) {
+
+	server := newWebhookServer(0)
+	defer server.Close()
+
+	clk := &clock{now: time.Unix(1000, 0)}
+
+	engine := NewEngine(Config{
+		Rules: []Rule{
+			CountAbove("spike", MatchAll(), 1, time.Minute),
+		},
+		Notifier: NewWebhookNotifier(server.URL),
+		Now:      clk.Now,
+	})
+
+	engine.Observe(context.Background(), core.New().Build())
+
+	clk.now = clk.now.Add(2 * time.Minute)
+
+	engine.Observe(context.Background(), core.New().Build())
+	engine.Wait()
+
+	if len(server.received()) != 0 {
+		t.Fatal("Errors outside the window were counted")
+	}
+}
+
+func TestEngine_RatioRule(t *testing.T) {
+
+	server := newWebhookServer(0)
+	defer server.Close()
+
+	engine := NewEngine(Config{
+		Rules: []Rule{
+			RatioAbove("5xx-ratio", MatchStatus(500, 599), 0.05, time.Minute),
+		},
+		Notifier: NewWebhookNotifier(server.URL),
+	})
+
+	for i := 0; i < 100; i++ {
+		engine.RecordRequest()
+	}
+
+	for i := 0; i < 5; i++ {
+		engine.Observe(context.Background(), core.New().Build())
+	}
+
+	engine.Wait()
+
+	if len(server.received()) != 0 {
+		t.Fatal("Ratio at threshold should not fire")
+	}
+
+	engine.Observe(context.Background(), core.New().Build())
+	engine.Wait()
+
+	if len(server.received()) != 1 {
+		t.Fatal("Ratio above threshold should fire")
+	}
+}
+
+func TestEngine_RateLimit(t *testing.T) {
+
+	server := newWebhookServer(0)
+	defer server.Close()
+
+	engine := NewEngine(Config{
+		Rules: []Rule{
+			Any("fatal", MatchLevel(core.LevelFatal)),
+			Any("any", MatchAll()),
+		},
+		Notifier:     NewWebhookNotifier(server.URL),
+		MaxPerMinute: 1,
+	})
+
+	engine.Observe(context.Background(), core.New().WithLevel(core.LevelFatal).Build())
+	engine.Wait()
+
+	if len(server.received()) != 1 || engine.RateLimited() != 1 {
+		t.Fatal("Notifications not rate limited")
+	}
+}
+
+func TestWebhookNotifier_Retries(t *testing.T) {
+
+	server := newWebhookServer(2)
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL).WithBackoff(time.Millisecond)
+
+	if err := notifier.Notify(context.Background(), Alert{Rule: "r"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(server.received()) != 1 {
+		t.Fatal("Alert not delivered after retries")
+	}
+}
+
+func TestWebhookNotifier_GivesUp(t *testing.T) {
+
+	server := newWebhookServer(10)
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL).WithRetries(1).WithBackoff(time.Millisecond)
+
+	if err := notifier.Notify(context.Background(), Alert{Rule: "r"}); err == nil {
+		t.Fatal("Expected delivery error")
+	}
+}
+
+type blockingNotifier struct {
+	started chan struct{}
+}
+
+func (n *blockingNotifier) Notify(ctx context.Context, alert Alert) error {
+
+	close(n.started)
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+func TestEngine_CloseCancelsInFlight(t *testing.T) {
+
+	notifier := &blockingNotifier{started: make(chan struct{})}
+
+	var failed error
+
+	engine := NewEngine(Config{
+		Rules:    []Rule{Any("any", MatchAll())},
+		Notifier: notifier,
+		OnError:  func(alert Alert, err error) { failed = err },
+	})
+
+	engine.Observe(context.Background(), core.New().Build())
+	<-notifier.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := engine.Close(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+
+	if failed != context.Canceled {
+		t.Fatalf("In-flight notification not canceled, got %v", failed)
+	}
+}
@@ -0,0 +1,75 @@
+package alerting
+
+import (
+	"time"
+
+	"github.com/krisalay/error-framework/core"
+)
+
+// Matcher selects the errors a rule counts
+type Matcher func(err *core.AppError) bool
+
+func MatchAll() Matcher {
+	return func(err *core.AppError) bool {
+		return true
+	}
+}
+
+func MatchCode(code string) Matcher {
+	return func(err *core.AppError) bool {
+		return err.Code == code
+	}
+}
+
+// MatchLevel matches errors at or above level
+func MatchLevel(level core.ErrorLevel) Matcher {
+	return func(err *core.AppError) bool {
+		return err.Level >= level
+	}
+}
+
+// MatchStatus matches errors whose status is within [min, max]
+func MatchStatus(min, max int) Matcher {
+	return func(err *core.AppError) bool {
+		return err.Status >= min && err.Status <= max
+	}
+}
+
+type Rule struct {
+	Name      string
+	Match     Matcher
+	Window    time.Duration
+	Threshold float64 // matched errors, or the matched share of requests when Ratio is set
+
+	// Ratio compares matched errors to requests recorded with RecordRequest
+	Ratio bool
+	// MinRequests avoids firing ratio rules on low traffic
+	MinRequests int
+}
+
+// CountAbove fires when more than threshold matching errors occur within window
+func CountAbove(name string, match Matcher, threshold int, window time.Duration) Rule {
+	return Rule{
+		Name:      name,
+		Match:     match,
+		Window:    window,
+		Threshold: float64(threshold),
+	}
+}
+
+// Any fires on every matching error, subject to deduplication
+func Any(name string, match Matcher) Rule {
+	return CountAbove(name, match, 0, time.Minute)
+}
+
+// RatioAbove fires when matching errors exceed ratio of the requests within window
+func RatioAbove(name string, match Matcher, ratio float64, window time.Duration) Rule {
+	return Rule{
+		Name:        name,
+		Match:       match,
+		Window:      window,
+		Threshold:   ratio,
+		Ratio:       true,
+		MinRequests: 20,
+	}
+}
@@ -0,0 +1,113 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookNotifier posts alerts as JSON to a URL, retrying failed deliveries
+type WebhookNotifier struct {
+	url     string
+	client  *http.Client
+	retries int
+	backoff time.Duration
+	headers map[string]string
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:     url,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		retries: 3,
+		backoff: 500 * time.Millisecond,
+		headers: make(map[string]string),
+	}
+}
+
+func (w *WebhookNotifier) WithClient(client *http.Client) *WebhookNotifier {
+	w.client = client
+	return w
+}
+
+func (w *WebhookNotifier) WithRetries(retries int) *WebhookNotifier {
+	w.retries = retries
+	return w
+}
+
+// WithBackoff sets the delay before the first retry, doubled on each attempt
+func (w *WebhookNotifier) WithBackoff(backoff time.Duration) *WebhookNotifier {
+	w.backoff = backoff
+	return w
+}
+
+func (w *WebhookNotifier) WithHeader(key string, value string) *WebhookNotifier {
+	w.headers[key] = value
+	return w
+}
+
+func (w *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+
+	body, err := json.Marshal(alert)
+
+	if err != nil {
+		return err
+	}
+
+	backoff := w.backoff
+
+	for attempt := 0; ; attempt++ {
+
+		retry, err := w.send(ctx, body)
+
+		if err == nil {
+			return nil
+		}
+
+		if !retry || attempt >= w.retries {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// send delivers the payload once and reports whether a failure is worth retrying
+func (w *WebhookNotifier) send(ctx context.Context, body []byte) (bool, error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for key, value := range w.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := w.client.Do(req)
+
+	if err != nil {
+		return true, err
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+
+	return retry, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+}
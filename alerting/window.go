@@ -0,0 +1,51 @@
+package alerting
+
+import "time"
+
+const windowBuckets = 60
+
+// slidingCounter counts events over a sliding window using fixed-width buckets
+type slidingCounter struct {
+	width  time.Duration
+	counts [windowBuckets]uint64
+	stamps [windowBuckets]int64
+}
+
+func newSlidingCounter(window time.Duration) *slidingCounter {
+
+	width := window / windowBuckets
+
+	if width <= 0 {
+		width = time.Nanosecond
+	}
+
+	return &slidingCounter{width: width}
+}
+
+func (s *slidingCounter) add(now time.Time) {
+
+	slot := now.UnixNano() / int64(s.width)
+	i := slot % windowBuckets
+
+	if s.stamps[i] != slot {
+		s.stamps[i] = slot
+		s.counts[i] = 0
+	}
+
+	s.counts[i]++
+}
+
+func (s *slidingCounter) sum(now time.Time) uint64 {
+
+	current := now.UnixNano() / int64(s.width)
+
+	var total uint64
+
+	for i := range s.counts {
+		if current-s.stamps[i] < windowBuckets {
+			total += s.counts[i]
+		}
+	}
+
+	return total
+}
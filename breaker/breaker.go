@@ -0,0 +1,337 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/krisalay/error-framework/core"
+)
+
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+type Config struct {
+	Name string
+
+	// FailureThreshold is the number of consecutive failures that opens the breaker, defaults to 5
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before probing, defaults to 30s
+	OpenTimeout time.Duration
+	// HalfOpenMaxCalls limits concurrent probes while half-open, defaults to 1
+	HalfOpenMaxCalls int
+
+	// IsFailure overrides IsFailure for classifying errors
+	IsFailure func(err error) bool
+
+	// Manager receives state transitions as CIRCUIT_STATE_CHANGED events, optional.
+	// They are logged and published to subscribers without reaching observers.
+	Manager *core.Manager
+
+	// OnStateChange receives state transitions as well, optional.
+	// It runs after the breaker's lock is released.
+	OnStateChange func(ctx context.Context, change StateChange)
+
+	Now func() time.Time
+}
+
+// StateChange describes a breaker transition
+type StateChange struct {
+	Breaker string
+	From    State
+	To      State
+	At      time.Time
+}
+
+type Breaker struct {
+	config Config
+
+	mu        sync.Mutex
+	state     State
+	failures  int
+	openUntil time.Time
+	probes    int
+
+	// generation changes on every transition, so results of calls admitted
+	// in an earlier state are ignored
+	generation uint64
+
+	// transitions are reported after the lock is released
+	transitions []StateChange
+}
+
+func New(config Config) *Breaker {
+
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 5
+	}
+
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = 30 * time.Second
+	}
+
+	if config.HalfOpenMaxCalls <= 0 {
+		config.HalfOpenMaxCalls = 1
+	}
+
+	if config.IsFailure == nil {
+		config.IsFailure = IsFailure
+	}
+
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	return &Breaker{
+		config: config,
+	}
+}
+
+// IsFailure reports whether err indicates an unhealthy dependency.
+// Connection errors, timeouts and 5xx AppErrors count; client errors, cancellations
+// and other unclassified errors do not.
+func IsFailure(err error) bool {
+
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var appErr *core.AppError
+
+	if errors.As(err, &appErr) {
+
+		switch appErr.Code {
+		case core.CodeDBConnectionError, core.CodeTimeout:
+			return true
+		}
+
+		return appErr.Status >= 500
+	}
+
+	var netErr net.Error
+
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+func (b *Breaker) State() State {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// Execute runs fn unless the breaker is open.
+// An open breaker returns a CIRCUIT_OPEN AppError with status 503.
+// A panic in fn counts as a failure and is propagated.
+func (b *Breaker) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
+
+	generation, err := b.allow(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Recorded in a defer so a panicking probe still releases its half-open slot
+	failed := true
+
+	defer func() {
+		b.record(ctx, generation, failed)
+	}()
+
+	result := fn(ctx)
+
+	failed = b.config.IsFailure(result)
+
+	return result
+}
+
+// allow admits a call and returns the generation it was admitted in
+func (b *Breaker) allow(ctx context.Context) (uint64, *core.AppError) {
+
+	b.mu.Lock()
+	defer b.report(ctx)
+
+	now := b.config.Now()
+
+	if b.state == StateOpen {
+
+		if now.Before(b.openUntil) {
+			return 0, b.openError(b.openUntil.Sub(now))
+		}
+
+		b.transition(StateHalfOpen)
+	}
+
+	if b.state == StateHalfOpen {
+
+		if b.probes >= b.config.HalfOpenMaxCalls {
+			return 0, b.openError(0)
+		}
+
+		b.probes++
+	}
+
+	return b.generation, nil
+}
+
+// record applies a call's result unless the breaker changed state since it was admitted
+func (b *Breaker) record(ctx context.Context, generation uint64, failed bool) {
+
+	b.mu.Lock()
+	defer b.report(ctx)
+
+	if generation != b.generation {
+		return
+	}
+
+	switch b.state {
+
+	case StateHalfOpen:
+		b.probes--
+
+		if failed {
+			b.open()
+		} else {
+			b.failures = 0
+			b.transition(StateClosed)
+		}
+
+	case StateClosed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+
+		b.failures++
+
+		if b.failures >= b.config.FailureThreshold {
+			b.open()
+		}
+	}
+}
+
+func (b *Breaker) open() {
+
+	b.openUntil = b.config.Now().Add(b.config.OpenTimeout)
+	b.transition(StateOpen)
+}
+
+func (b *Breaker) transition(to State) {
+
+	from := b.state
+
+	if from == to {
+		return
+	}
+
+	b.state = to
+	b.generation++
+
+	if to != StateHalfOpen {
+		b.probes = 0
+	}
+
+	if b.config.Manager == nil && b.config.OnStateChange == nil {
+		return
+	}
+
+	b.transitions = append(b.transitions, StateChange{
+		Breaker: b.config.Name,
+		From:    from,
+		To:      to,
+		At:      b.config.Now(),
+	})
+}
+
+// report unlocks the breaker and hands pending transitions to the manager and OnStateChange
+func (b *Breaker) report(ctx context.Context) {
+
+	transitions := b.transitions
+	b.transitions = nil
+
+	b.mu.Unlock()
+
+	for _, transition := range transitions {
+
+		if b.config.Manager != nil {
+			b.config.Manager.Report(ctx, transition.event())
+		}
+
+		if b.config.OnStateChange != nil {
+			b.config.OnStateChange(ctx, transition)
+		}
+	}
+}
+
+// event describes the transition as a CIRCUIT_STATE_CHANGED AppError
+func (c StateChange) event() *core.AppError {
+
+	level := core.LevelInfo
+	if c.To == StateOpen {
+		level = core.LevelWarn
+	}
+
+	return core.New().
+		WithMessage(fmt.Sprintf("circuit breaker %s %s", c.Breaker, c.To)).
+		WithCode(core.CodeCircuitStateChanged).
+		WithStatus(http.StatusOK).
+		WithLevel(level).
+		WithSensitive(false).
+		WithDetail("breaker", c.Breaker).
+		WithDetail("from", c.From.String()).
+		WithDetail("to", c.To.String()).
+		Build()
+}
+
+func (b *Breaker) openError(wait time.Duration) *core.AppError {
+
+	// Half-open rejections retry once the running probe finishes
+	retryAfter := int(math.Ceil(wait.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+
+	return core.New().
+		WithMessage("Service temporarily unavailable").
+		WithCode(core.CodeCircuitOpen).
+		WithStatus(http.StatusServiceUnavailable).
+		WithLevel(core.LevelWarn).
+		WithSensitive(false).
+		WithDetail("breaker", b.config.Name).
+		WithDetail(core.DetailRetryAfter, retryAfter).
+		Build()
+}
@@ -0,0 +1,248 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/krisalay/error-framework/core"
+	"github.com/krisalay/error-framework/errtest"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func fail(err error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return err
+	}
+}
+
+func TestIsFailure(t *testing.T) {
+
+	cases := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"connection": {core.New().WithCode(core.CodeDBConnectionError).Build(), true},
+		"timeout":    {context.DeadlineExceeded, true},
+		"internal":   {core.New().Build(), true},
+		"not found":  {core.New().WithCode(core.CodeNotFound).WithStatus(404).Build(), false},
+		"validation": {core.New().WithCode(core.CodeValidationError).WithStatus(400).Build(), false},
+		"canceled":   {context.Canceled, false},
+		"success":    {nil, false},
+		"refused":    {&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, true},
+		"reset":      {syscall.ECONNRESET, true},
+		"plain":      {errors.New("bad input"), false},
+	}
+
+	for name, c := range cases {
+		if IsFailure(c.err) != c.expected {
+			t.Fatalf("%s: expected %v", name, c.expected)
+		}
+	}
+}
+
+func TestBreaker_OpensAndRecovers(t *testing.T) {
+
+	var changes []StateChange
+	clk := &clock{now: time.Unix(1000, 0)}
+
+	breaker := New(Config{
+		Name:             "db",
+		FailureThreshold: 2,
+		OpenTimeout:      10 * time.Second,
+		OnStateChange: func(ctx context.Context, change StateChange) {
+			changes = append(changes, change)
+		},
+		Now: clk.Now,
+	})
+
+	ctx := context.Background()
+	connErr := core.New().WithCode(core.CodeDBConnectionError).Build()
+
+	// Client errors never open the breaker
+	for i := 0; i < 5; i++ {
+		breaker.Execute(ctx, fail(core.New().WithCode(core.CodeNotFound).WithStatus(404).Build()))
+	}
+
+	if breaker.State() != StateClosed {
+		t.Fatal("Client errors opened the breaker")
+	}
+
+	breaker.Execute(ctx, fail(connErr))
+	breaker.Execute(ctx, fail(connErr))
+
+	if breaker.State() != StateOpen {
+		t.Fatal("Breaker not open after threshold")
+	}
+
+	err := breaker.Execute(ctx, fail(nil))
+
+	var appErr *core.AppError
+
+	if !errors.As(err, &appErr) || appErr.Code != core.CodeCircuitOpen || appErr.Status != 503 {
+		t.Fatalf("Unexpected open error: %v", err)
+	}
+
+	if appErr.Details[core.DetailRetryAfter] != 10 {
+		t.Fatalf("Unexpected retry after: %v", appErr.Details[core.DetailRetryAfter])
+	}
+
+	clk.now = clk.now.Add(11 * time.Second)
+
+	if err := breaker.Execute(ctx, fail(nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	if breaker.State() != StateClosed {
+		t.Fatal("Successful probe did not close the breaker")
+	}
+
+	expected := []State{StateOpen, StateHalfOpen, StateClosed}
+
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d reported transitions, got %d", len(expected), len(changes))
+	}
+
+	for i, change := range changes {
+		if change.To != expected[i] || change.Breaker != "db" {
+			t.Fatalf("Unexpected transition %d: %+v", i, change)
+		}
+	}
+}
+
+func TestBreaker_PanickingProbeReleasesSlot(t *testing.T) {
+
+	clk := &clock{now: time.Unix(1000, 0)}
+
+	breaker := New(Config{
+		FailureThreshold: 1,
+		OpenTimeout:      10 * time.Second,
+		Now:              clk.Now,
+	})
+
+	ctx := context.Background()
+
+	breaker.Execute(ctx, fail(context.DeadlineExceeded))
+	clk.now = clk.now.Add(11 * time.Second)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Panic not propagated")
+			}
+		}()
+
+		breaker.Execute(ctx, func(ctx context.Context) error {
+			panic("probe failed")
+		})
+	}()
+
+	if breaker.State() != StateOpen {
+		t.Fatal("Panicking probe did not reopen the breaker")
+	}
+
+	clk.now = clk.now.Add(11 * time.Second)
+
+	if err := breaker.Execute(ctx, fail(nil)); err != nil {
+		t.Fatalf("Half-open slot leaked: %v", err)
+	}
+}
+
+func TestBreaker_ReportsTransitionsThroughManager(t *testing.T) {
+
+	logger := errtest.NewRecorder()
+	manager := core.NewManager(core.ManagerConfig{Logger: logger})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subscription := manager.Subscribe(ctx, core.EventFilter{Codes: []string{core.CodeCircuitStateChanged}}, 1)
+
+	breaker := New(Config{
+		Name:             "db",
+		FailureThreshold: 1,
+		Manager:          manager,
+	})
+
+	breaker.Execute(ctx, fail(context.DeadlineExceeded))
+
+	logged := errtest.AssertLogged(t, logger, core.CodeCircuitStateChanged)
+
+	if logged.Details["to"] != "open" || logged.Level != core.LevelWarn {
+		t.Fatalf("Unexpected transition event: %+v", logged.Details)
+	}
+
+	select {
+	case event := <-subscription.Events():
+		if event.Error != logged {
+			t.Fatal("Published event does not match the logged transition")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Transition not published to subscribers")
+	}
+}
+
+func TestBreaker_IgnoresResultsFromEarlierState(t *testing.T) {
+
+	clk := &clock{now: time.Unix(1000, 0)}
+
+	breaker := New(Config{
+		FailureThreshold: 1,
+		OpenTimeout:      10 * time.Second,
+		Now:              clk.Now,
+	})
+
+	ctx := context.Background()
+
+	block := func(release chan struct{}, started chan struct{}) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			close(started)
+			<-release
+			return nil
+		}
+	}
+
+	staleRelease, staleStarted, staleDone := make(chan struct{}), make(chan struct{}), make(chan struct{})
+
+	// Admitted while closed, finishes after the breaker went half-open
+	go func() {
+		defer close(staleDone)
+		breaker.Execute(ctx, block(staleRelease, staleStarted))
+	}()
+	<-staleStarted
+
+	breaker.Execute(ctx, fail(context.DeadlineExceeded))
+	clk.now = clk.now.Add(11 * time.Second)
+
+	probeRelease, probeStarted, probeDone := make(chan struct{}), make(chan struct{}), make(chan struct{})
+
+	go func() {
+		defer close(probeDone)
+		breaker.Execute(ctx, block(probeRelease, probeStarted))
+	}()
+	<-probeStarted
+
+	close(staleRelease)
+	<-staleDone
+
+	if breaker.State() != StateHalfOpen {
+		t.Fatalf("Stale success changed the half-open breaker to %s", breaker.State())
+	}
+
+	close(probeRelease)
+	<-probeDone
+
+	if breaker.State() != StateClosed {
+		t.Fatalf("Probe did not close the breaker, got %s", breaker.State())
+	}
+}
@@ -33,6 +33,11 @@ func (b *Builder) WithCode(code string) *Builder {
 	return b
 }
 
+func (b *Builder) WithDomain(domain string) *Builder {
+	b.err.Domain = domain
+	return b
+}
+
 func (b *Builder) WithStatus(status int) *Builder {
 	b.err.Status = status
 	return b
@@ -48,6 +53,16 @@ func (b *Builder) WithDetail(key string, value any) *Builder {
 	return b
 }
 
+func (b *Builder) WithAttribute(key string, value any) *Builder {
+
+	if b.err.Attributes == nil {
+		b.err.Attributes = make(map[string]any)
+	}
+
+	b.err.Attributes[key] = value
+	return b
+}
+
 func (b *Builder) WithLevel(level ErrorLevel) *Builder {
 	b.err.Level = level
 	return b
@@ -68,6 +83,11 @@ func (b *Builder) WithTraceID(traceID string) *Builder {
 	return b
 }
 
+func (b *Builder) WithSpanID(spanID string) *Builder {
+	b.err.SpanID = spanID
+	return b
+}
+
 func (b *Builder) WithStackTrace(stack string) *Builder {
 	b.err.StackTrace = stack
 	return b
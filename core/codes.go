@@ -26,5 +26,50 @@ const (
 	CodeDBConnectionError = "DB_CONNECTION_ERROR"
 
 	// Network Errors
-	CodeTimeout = "TIMEOUT"
+	CodeTimeout             = "TIMEOUT"
+	CodeCircuitOpen         = "CIRCUIT_OPEN"
+	CodeCircuitStateChanged = "CIRCUIT_STATE_CHANGED"
+	CodeDownstreamError     = "DOWNSTREAM_ERROR"
 )
+
+// DetailRetryAfter holds the seconds a client should wait before retrying
+const DetailRetryAfter = "retry_after"
+
+const (
+	DomainGeneric    = "generic"
+	DomainValidation = "validation"
+	DomainAuth       = "auth"
+	DomainResource   = "resource"
+	DomainDatabase   = "database"
+	DomainNetwork    = "network"
+)
+
+var codeDomains = map[string]string{
+	CodeInternalError:       DomainGeneric,
+	CodeUnknownError:        DomainGeneric,
+	CodeValidationError:     DomainValidation,
+	CodeInvalidInput:        DomainValidation,
+	CodeUnauthorized:        DomainAuth,
+	CodeForbidden:           DomainAuth,
+	CodeNotFound:            DomainResource,
+	CodeAlreadyExists:       DomainResource,
+	CodeDBError:             DomainDatabase,
+	CodeDBDuplicateKey:      DomainDatabase,
+	CodeDBForeignKey:        DomainDatabase,
+	CodeDBNoRows:            DomainDatabase,
+	CodeDBConnectionError:   DomainDatabase,
+	CodeTimeout:             DomainNetwork,
+	CodeCircuitOpen:         DomainNetwork,
+	CodeCircuitStateChanged: DomainNetwork,
+	CodeDownstreamError:     DomainNetwork,
+}
+
+// DomainForCode returns the domain a built-in code belongs to, or DomainGeneric
+func DomainForCode(code string) string {
+
+	if domain, ok := codeDomains[code]; ok {
+		return domain
+	}
+
+	return DomainGeneric
+}
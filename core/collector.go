@@ -0,0 +1,123 @@
+package core
+
+import (
+	"context"
+	"sync"
+)
+
+type collectorKey struct{}
+
+// Warning is a client-visible note about a degraded but successful operation
+type Warning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Collector gathers soft errors and warnings raised while serving one request
+type Collector struct {
+	mu       sync.Mutex
+	warnings []Warning
+	errors   []error
+
+	// flushed counts warnings already logged; they stay readable for the response
+	flushed int
+}
+
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+// WithCollector attaches a collector to the context
+func WithCollector(ctx context.Context, collector *Collector) context.Context {
+	return context.WithValue(ctx, collectorKey{}, collector)
+}
+
+// CollectorFromContext returns the request's collector, or nil
+func CollectorFromContext(ctx context.Context) *Collector {
+
+	if ctx == nil {
+		return nil
+	}
+
+	collector, _ := ctx.Value(collectorKey{}).(*Collector)
+
+	return collector
+}
+
+// AddWarning records a warning on the request's collector.
+// It reports false when the context carries no collector.
+func AddWarning(ctx context.Context, code string, message string) bool {
+
+	collector := CollectorFromContext(ctx)
+
+	if collector == nil {
+		return false
+	}
+
+	collector.AddWarning(code, message)
+
+	return true
+}
+
+// AddError records a non-fatal error on the request's collector.
+// It reports false when the context carries no collector.
+func AddError(ctx context.Context, err error) bool {
+
+	collector := CollectorFromContext(ctx)
+
+	if collector == nil || err == nil {
+		return false
+	}
+
+	collector.AddError(err)
+
+	return true
+}
+
+func (c *Collector) AddWarning(code string, message string) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.warnings = append(c.warnings, Warning{Code: code, Message: message})
+}
+
+func (c *Collector) AddError(err error) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.errors = append(c.errors, err)
+}
+
+func (c *Collector) Warnings() []Warning {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Warning(nil), c.warnings...)
+}
+
+func (c *Collector) Errors() []error {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]error(nil), c.errors...)
+}
+
+// drain returns what was collected since the last drain.
+// Errors are cleared, warnings are kept so responses can still carry them.
+func (c *Collector) drain() ([]Warning, []error) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	warnings := append([]Warning(nil), c.warnings[c.flushed:]...)
+	c.flushed = len(c.warnings)
+
+	errs := c.errors
+	c.errors = nil
+
+	return warnings, errs
+}
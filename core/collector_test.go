@@ -0,0 +1,66 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type staticTraceProvider struct{}
+
+func (s *staticTraceProvider) GetTraceID(ctx context.Context) string {
+	return "trace-1"
+}
+
+func TestCollector_WithoutContextCollector(t *testing.T) {
+
+	if AddWarning(context.Background(), "DEGRADED", "cache unavailable") {
+		t.Fatal("Warning recorded without collector")
+	}
+}
+
+func TestManager_FlushCollected(t *testing.T) {
+
+	logger := &mockLogger{}
+	observer := &recordingObserver{}
+
+	manager := NewManager(ManagerConfig{
+		Logger:        logger,
+		Observers:     []Observer{observer},
+		TraceProvider: &staticTraceProvider{},
+	})
+
+	collector := NewCollector()
+	ctx := WithCollector(context.Background(), collector)
+
+	AddWarning(ctx, "DEGRADED", "recommendations unavailable")
+	AddError(ctx, errors.New("cache miss"))
+
+	handled := manager.FlushCollected(ctx)
+
+	if len(handled) != 2 || logger.count != 2 {
+		t.Fatalf("Expected 2 logged entries, got %d", logger.count)
+	}
+
+	for _, appErr := range handled {
+		if appErr.TraceID != "trace-1" {
+			t.Fatal("Trace ID not attached")
+		}
+	}
+
+	if handled[0].Level != LevelWarn {
+		t.Fatal("Warning not logged at warn level")
+	}
+
+	if len(observer.observed) != 1 || observer.observed[0] != handled[1] {
+		t.Fatal("Warnings must not reach observers")
+	}
+
+	if len(collector.Warnings()) != 1 {
+		t.Fatal("Warnings dropped from the response after flushing")
+	}
+
+	if len(manager.FlushCollected(ctx)) != 0 {
+		t.Fatal("Collected entries flushed twice")
+	}
+}
@@ -0,0 +1,216 @@
+package core
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Environment string
+
+const (
+	EnvDevelopment Environment = "dev"
+	EnvStaging     Environment = "staging"
+	EnvProduction  Environment = "prod"
+)
+
+// ProductionMarkerEnv is the environment variable that marks a production host.
+// Development mode is refused while it is set.
+const ProductionMarkerEnv = "ERRORFRAMEWORK_PRODUCTION"
+
+// HeaderDebugToken carries a signed token that unlocks debug output in staging
+const HeaderDebugToken = "X-Debug-Token"
+
+const debugTokenTTL = 15 * time.Minute
+
+type debugTokenKey struct{}
+
+type debugClientKey struct{}
+
+// DebugInfo is the internal view of an error, exposed only outside production
+type DebugInfo struct {
+	Message string         `json:"message"`
+	Code    string         `json:"code"`
+	Causes  []string       `json:"causes,omitempty"`
+	Stack   []string       `json:"stack,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// DebugPolicy decides whether error internals may be exposed to clients
+type DebugPolicy struct {
+	env      Environment
+	secret   []byte
+	networks []netip.Prefix
+	now      func() time.Time
+}
+
+// NewDebugPolicy creates a policy for env.
+// Staging requires a secret to verify debug tokens.
+func NewDebugPolicy(env Environment, secret string) (*DebugPolicy, error) {
+
+	switch env {
+
+	case EnvDevelopment:
+		if marker := os.Getenv(ProductionMarkerEnv); marker != "" && marker != "0" && marker != "false" {
+			return nil, fmt.Errorf("development mode refused: %s is set", ProductionMarkerEnv)
+		}
+
+	case EnvStaging:
+		if secret == "" {
+			return nil, errors.New("staging mode requires a debug secret")
+		}
+
+	case EnvProduction, "":
+		env = EnvProduction
+
+	default:
+		return nil, fmt.Errorf("unknown environment %q", env)
+	}
+
+	return &DebugPolicy{
+		env:    env,
+		secret: []byte(secret),
+		now:    time.Now,
+	}, nil
+}
+
+func (p *DebugPolicy) Environment() Environment {
+	return p.env
+}
+
+// WithAllowedNetworks restricts staging debug tokens to clients within networks.
+// The client address comes from WithDebugClient; requests without one are refused.
+func (p *DebugPolicy) WithAllowedNetworks(networks ...netip.Prefix) *DebugPolicy {
+	p.networks = append(p.networks, networks...)
+	return p
+}
+
+// Allowed reports whether the request may see error internals
+func (p *DebugPolicy) Allowed(ctx context.Context) bool {
+
+	if p == nil {
+		return false
+	}
+
+	switch p.env {
+
+	case EnvDevelopment:
+		return true
+
+	case EnvStaging:
+		if ctx == nil {
+			return false
+		}
+
+		token, _ := ctx.Value(debugTokenKey{}).(string)
+
+		return p.clientAllowed(ctx) && p.verify(token)
+
+	default:
+		return false
+	}
+}
+
+func (p *DebugPolicy) clientAllowed(ctx context.Context) bool {
+
+	if len(p.networks) == 0 {
+		return true
+	}
+
+	client, _ := ctx.Value(debugClientKey{}).(string)
+
+	addr, err := netip.ParseAddr(client)
+
+	if err != nil {
+		return false
+	}
+
+	addr = addr.Unmap()
+
+	for _, network := range p.networks {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *DebugPolicy) verify(token string) bool {
+
+	issued, signature, ok := strings.Cut(token, ".")
+
+	if !ok {
+		return false
+	}
+
+	unix, err := strconv.ParseInt(issued, 10, 64)
+
+	if err != nil {
+		return false
+	}
+
+	age := p.now().Sub(time.Unix(unix, 0))
+
+	if age < -time.Minute || age > debugTokenTTL {
+		return false
+	}
+
+	expected := sign(p.secret, issued)
+
+	return hmac.Equal([]byte(signature), []byte(expected))
+}
+
+// SignDebugToken creates a token for HeaderDebugToken, valid for 15 minutes after at
+func SignDebugToken(secret string, at time.Time) string {
+
+	issued := strconv.FormatInt(at.Unix(), 10)
+
+	return issued + "." + sign([]byte(secret), issued)
+}
+
+func sign(secret []byte, issued string) string {
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(issued))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// WithDebugToken stores the request's debug token for the policy to verify
+func WithDebugToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, debugTokenKey{}, token)
+}
+
+// WithDebugClient stores the client IP checked against the policy's allowed networks
+func WithDebugClient(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, debugClientKey{}, ip)
+}
+
+// NewDebugInfo collects the internals of err
+func NewDebugInfo(err *AppError) *DebugInfo {
+
+	info := &DebugInfo{
+		Message: err.Message,
+		Code:    err.Code,
+		Causes:  err.Causes(),
+		Details: err.Details,
+	}
+
+	// Stack traces are "function\n\tfile:line\n" pairs
+	lines := strings.Split(strings.TrimSpace(err.StackTrace), "\n")
+
+	for i := 0; i+1 < len(lines); i += 2 {
+		info.Stack = append(info.Stack, strings.TrimSpace(lines[i])+" "+strings.TrimSpace(lines[i+1]))
+	}
+
+	return info
+}
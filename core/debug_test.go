@@ -0,0 +1,119 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestDebugPolicy_Production(t *testing.T) {
+
+	policy, err := NewDebugPolicy(EnvProduction, "secret")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithDebugToken(context.Background(), SignDebugToken("secret", time.Now()))
+
+	if policy.Allowed(ctx) {
+		t.Fatal("Production must never expose internals")
+	}
+}
+
+func TestDebugPolicy_StagingRequiresValidToken(t *testing.T) {
+
+	policy, err := NewDebugPolicy(EnvStaging, "secret")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if policy.Allowed(context.Background()) {
+		t.Fatal("Staging exposed internals without token")
+	}
+
+	if policy.Allowed(WithDebugToken(context.Background(), SignDebugToken("wrong", time.Now()))) {
+		t.Fatal("Staging accepted token with wrong signature")
+	}
+
+	if policy.Allowed(WithDebugToken(context.Background(), SignDebugToken("secret", time.Now().Add(-time.Hour)))) {
+		t.Fatal("Staging accepted expired token")
+	}
+
+	if !policy.Allowed(WithDebugToken(context.Background(), SignDebugToken("secret", time.Now()))) {
+		t.Fatal("Staging rejected valid token")
+	}
+}
+
+func TestDebugPolicy_StagingAllowedNetworks(t *testing.T) {
+
+	policy, err := NewDebugPolicy(EnvStaging, "secret")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy.WithAllowedNetworks(netip.MustParsePrefix("10.0.0.0/8"))
+
+	ctx := WithDebugToken(context.Background(), SignDebugToken("secret", time.Now()))
+
+	if policy.Allowed(ctx) {
+		t.Fatal("Staging accepted token without client address")
+	}
+
+	if policy.Allowed(WithDebugClient(ctx, "203.0.113.7")) {
+		t.Fatal("Staging accepted token from outside the allowed networks")
+	}
+
+	if !policy.Allowed(WithDebugClient(ctx, "10.1.2.3")) {
+		t.Fatal("Staging rejected token from an allowed network")
+	}
+}
+
+func TestDebugPolicy_DevRefusedInProduction(t *testing.T) {
+
+	t.Setenv(ProductionMarkerEnv, "1")
+
+	if _, err := NewDebugPolicy(EnvDevelopment, ""); err == nil {
+		t.Fatal("Development mode allowed with production marker")
+	}
+}
+
+func TestManager_ToResponse_DevExposesInternals(t *testing.T) {
+
+	t.Setenv(ProductionMarkerEnv, "")
+
+	policy, err := NewDebugPolicy(EnvDevelopment, "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewManager(ManagerConfig{
+		Logger: &mockLogger{},
+		Debug:  policy,
+	})
+
+	response := manager.ToResponse(context.Background(), errors.New("db password wrong"))
+
+	if response.Err == nil || response.Err.Error() != "db password wrong" {
+		t.Fatal("Cause not exposed in development")
+	}
+
+	_, body := manager.Respond(context.Background(), errors.New("db password wrong"))
+
+	debug, _ := body.(map[string]any)[FieldDebug].(*DebugInfo)
+
+	if debug == nil || len(debug.Causes) == 0 || debug.Causes[len(debug.Causes)-1] != "db password wrong" {
+		t.Fatal("Cause not rendered in development")
+	}
+
+	info := manager.DebugInfo(context.Background(), New().WithInternal(errors.New("root")).Build())
+
+	if info == nil || len(info.Causes) != 1 {
+		t.Fatal("Debug info missing cause chain")
+	}
+}
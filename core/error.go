@@ -1,12 +1,15 @@
 package core
 
 import (
+	"errors"
+	"sync/atomic"
 	"time"
 )
 
 type AppError struct {
 	Message string
 	Code    string
+	Domain  string
 	Status  int
 	Details map[string]any
 
@@ -17,6 +20,12 @@ type AppError struct {
 	Timestamp  time.Time
 	StackTrace string
 	TraceID    string
+	SpanID     string
+
+	// Attributes carry request context for logs only, never for clients
+	Attributes map[string]any `json:"-"`
+
+	handled atomic.Bool
 }
 
 // Implements Go error interface
@@ -44,3 +53,52 @@ func (e *AppError) SafeCode() string {
 	}
 	return e.Code
 }
+
+// MarkHandled records that the error has been handled and logged
+func (e *AppError) MarkHandled() {
+	e.handled.Store(true)
+}
+
+// IsHandled reports whether this error, or any AppError it wraps, has already been handled
+func (e *AppError) IsHandled() bool {
+
+	if e.handled.Load() {
+		return true
+	}
+
+	var cause *AppError
+
+	return errors.As(e.Err, &cause) && cause.IsHandled()
+}
+
+// markHandled marks the error and reports whether neither it nor a wrapped AppError was handled before
+func (e *AppError) markHandled() bool {
+
+	if !e.handled.CompareAndSwap(false, true) {
+		return false
+	}
+
+	var cause *AppError
+
+	return !errors.As(e.Err, &cause) || !cause.IsHandled()
+}
+
+// Causes returns the messages of the wrapped errors, outermost first
+func (e *AppError) Causes() []string {
+
+	var causes []string
+
+	for err := e.Err; err != nil; err = errors.Unwrap(err) {
+		causes = append(causes, err.Error())
+	}
+
+	return causes
+}
+
+// IsHandled reports whether any AppError in the chain of err has already been handled
+func IsHandled(err error) bool {
+
+	var appErr *AppError
+
+	return errors.As(err, &appErr) && appErr.IsHandled()
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -52,3 +53,31 @@ func TestSafeMessage_NotSensitive(t *testing.T) {
 		t.Fatal("Safe message incorrect")
 	}
 }
+
+func TestIsHandled_SurvivesWrapping(t *testing.T) {
+
+	inner := New().WithMessage("inner").Build()
+	inner.MarkHandled()
+
+	outer := New().WithMessage("outer").WithInternal(fmt.Errorf("ctx: %w", inner)).Build()
+
+	if !outer.IsHandled() {
+		t.Fatal("Handled state lost through wrapping")
+	}
+
+	if !IsHandled(fmt.Errorf("request: %w", outer)) {
+		t.Fatal("Handled state lost through fmt.Errorf wrapping")
+	}
+}
+
+func TestAppError_Causes(t *testing.T) {
+
+	inner := New().WithMessage("inner").WithInternal(errors.New("root")).Build()
+	outer := New().WithMessage("outer").WithInternal(inner).Build()
+
+	causes := outer.Causes()
+
+	if len(causes) != 2 || causes[0] != "inner" || causes[1] != "root" {
+		t.Fatalf("Unexpected causes: %v", causes)
+	}
+}
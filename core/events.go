@@ -0,0 +1,134 @@
+package core
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrorEvent describes an error handled by the manager
+type ErrorEvent struct {
+	Error *AppError
+	Panic bool
+	Time  time.Time
+}
+
+// EventFilter selects the events a subscription receives.
+// Zero values match everything.
+type EventFilter struct {
+	Codes    []string
+	MinLevel ErrorLevel
+	Domains  []string
+}
+
+func (f EventFilter) Match(event ErrorEvent) bool {
+
+	err := event.Error
+
+	if err.Level < f.MinLevel {
+		return false
+	}
+
+	if len(f.Codes) > 0 && !slices.Contains(f.Codes, err.Code) {
+		return false
+	}
+
+	if len(f.Domains) > 0 && !slices.Contains(f.Domains, err.Domain) {
+		return false
+	}
+
+	return true
+}
+
+type Subscription struct {
+	bus     *EventBus
+	filter  EventFilter
+	events  chan ErrorEvent
+	dropped atomic.Uint64
+	stop    func() bool
+	once    sync.Once
+}
+
+// Events returns the delivery channel, closed on unsubscribe
+func (s *Subscription) Events() <-chan ErrorEvent {
+	return s.events
+}
+
+// Dropped returns the number of events discarded because the buffer was full
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+func (s *Subscription) Unsubscribe() {
+
+	s.once.Do(func() {
+
+		if s.stop != nil {
+			s.stop()
+		}
+
+		s.bus.mu.Lock()
+		delete(s.bus.subscriptions, s)
+		s.bus.mu.Unlock()
+
+		close(s.events)
+	})
+}
+
+// EventBus delivers events to subscribers without blocking the publisher
+type EventBus struct {
+	mu            sync.RWMutex
+	subscriptions map[*Subscription]struct{}
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{
+		subscriptions: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe registers a subscriber with a buffer of the given size.
+// The subscription ends when ctx is canceled or Unsubscribe is called.
+func (b *EventBus) Subscribe(ctx context.Context, filter EventFilter, buffer int) *Subscription {
+
+	if buffer <= 0 {
+		buffer = 64
+	}
+
+	subscription := &Subscription{
+		bus:    b,
+		filter: filter,
+		events: make(chan ErrorEvent, buffer),
+	}
+
+	b.mu.Lock()
+	b.subscriptions[subscription] = struct{}{}
+	b.mu.Unlock()
+
+	if ctx != nil {
+		subscription.stop = context.AfterFunc(ctx, subscription.Unsubscribe)
+	}
+
+	return subscription
+}
+
+func (b *EventBus) Publish(event ErrorEvent) {
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for subscription := range b.subscriptions {
+
+		if !subscription.filter.Match(event) {
+			continue
+		}
+
+		select {
+		case subscription.events <- event:
+		default:
+			subscription.dropped.Add(1)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestManager_Subscribe_Filters(t *testing.T) {
+
+	manager := NewManager(ManagerConfig{
+		Logger: &mockLogger{},
+	})
+
+	subscription := manager.Subscribe(context.Background(), EventFilter{
+		Domains: []string{DomainDatabase},
+	}, 4)
+
+	manager.Handle(context.Background(), New().WithCode(CodeNotFound).Build())
+	manager.Handle(context.Background(), New().WithCode(CodeDBError).Build())
+
+	select {
+	case event := <-subscription.Events():
+		if event.Error.Code != CodeDBError {
+			t.Fatalf("Unexpected event %s", event.Error.Code)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Event not delivered")
+	}
+
+	select {
+	case event := <-subscription.Events():
+		t.Fatalf("Filtered event delivered: %s", event.Error.Code)
+	default:
+	}
+}
+
+func TestManager_Subscribe_DropsWhenFull(t *testing.T) {
+
+	manager := NewManager(ManagerConfig{
+		Logger: &mockLogger{},
+	})
+
+	subscription := manager.Subscribe(context.Background(), EventFilter{}, 1)
+
+	manager.Handle(context.Background(), New().Build())
+	manager.Handle(context.Background(), New().Build())
+	manager.HandlePanic(context.Background(), "boom")
+
+	if subscription.Dropped() != 2 {
+		t.Fatalf("Expected 2 dropped events, got %d", subscription.Dropped())
+	}
+}
+
+func TestManager_Subscribe_UnsubscribesOnCancel(t *testing.T) {
+
+	manager := NewManager(ManagerConfig{
+		Logger: &mockLogger{},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	subscription := manager.Subscribe(ctx, EventFilter{}, 1)
+
+	cancel()
+
+	select {
+	case _, ok := <-subscription.Events():
+		if ok {
+			t.Fatal("Unexpected event")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Subscription not closed on cancel")
+	}
+
+	// Publishing after unsubscribe must not panic
+	manager.Handle(context.Background(), New().Build())
+}
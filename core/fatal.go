@@ -0,0 +1,121 @@
+package core
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+)
+
+type FatalAction int
+
+const (
+	// FatalLogOnly logs fatal errors and keeps running
+	FatalLogOnly FatalAction = iota
+	// FatalExit runs the shutdown hooks and exits the process
+	FatalExit
+	// FatalEscalate hands fatal errors to the escalation observer and keeps running
+	FatalEscalate
+)
+
+// FatalPolicy decides what happens after a LevelFatal error is logged
+type FatalPolicy struct {
+	Action FatalAction
+
+	// ShutdownTimeout bounds the shutdown hooks before exiting, defaults to 10s
+	ShutdownTimeout time.Duration
+
+	// Escalation receives fatal errors for FatalEscalate, e.g. an alerting engine
+	Escalation Observer
+
+	// ExitOnPanic applies FatalExit to recovered panics too.
+	// By default a recovered panic never terminates the process.
+	ExitOnPanic bool
+
+	// Exit terminates the process, defaults to os.Exit
+	Exit func(code int)
+}
+
+// ShutdownHook releases resources before a fatal exit
+type ShutdownHook func(ctx context.Context)
+
+type fatalHandler struct {
+	policy FatalPolicy
+
+	mu    sync.Mutex
+	hooks []ShutdownHook
+
+	exitOnce sync.Once
+}
+
+func newFatalHandler(policy FatalPolicy) *fatalHandler {
+
+	if policy.ShutdownTimeout <= 0 {
+		policy.ShutdownTimeout = 10 * time.Second
+	}
+
+	if policy.Exit == nil {
+		policy.Exit = os.Exit
+	}
+
+	return &fatalHandler{policy: policy}
+}
+
+func (f *fatalHandler) register(hook ShutdownHook) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.hooks = append(f.hooks, hook)
+}
+
+func (f *fatalHandler) handle(ctx context.Context, err *AppError, panicked bool) {
+
+	if err.Level != LevelFatal {
+		return
+	}
+
+	switch f.policy.Action {
+
+	case FatalEscalate:
+		if f.policy.Escalation != nil {
+			f.policy.Escalation.Observe(ctx, err)
+		}
+
+	case FatalExit:
+		if panicked && !f.policy.ExitOnPanic {
+			return
+		}
+
+		f.exitOnce.Do(f.exit)
+	}
+}
+
+// exit runs the hooks in reverse registration order, then terminates
+func (f *fatalHandler) exit() {
+
+	f.mu.Lock()
+	hooks := append([]ShutdownHook(nil), f.hooks...)
+	f.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), f.policy.ShutdownTimeout)
+	defer cancel()
+
+	done := make(chan struct{})
+
+	go func() {
+
+		defer close(done)
+
+		for i := len(hooks) - 1; i >= 0; i-- {
+			hooks[i](ctx)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+
+	f.policy.Exit(1)
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type recordingObserver struct {
+	observed []*AppError
+}
+
+func (r *recordingObserver) Observe(ctx context.Context, err *AppError) {
+	r.observed = append(r.observed, err)
+}
+
+func TestFatalPolicy_ExitRunsHooks(t *testing.T) {
+
+	exitCode := -1
+	hookRan := false
+
+	manager := NewManager(ManagerConfig{
+		Logger: &mockLogger{},
+		Fatal: FatalPolicy{
+			Action: FatalExit,
+			Exit:   func(code int) { exitCode = code },
+		},
+	})
+
+	manager.OnShutdown(func(ctx context.Context) {
+		hookRan = true
+	})
+
+	manager.Handle(context.Background(), New().WithLevel(LevelFatal).Build())
+
+	if !hookRan || exitCode != 1 {
+		t.Fatal("Fatal exit did not run hooks before exiting")
+	}
+}
+
+func TestFatalPolicy_RecoveredPanicDoesNotExit(t *testing.T) {
+
+	exited := false
+
+	manager := NewManager(ManagerConfig{
+		Logger: &mockLogger{},
+		Fatal: FatalPolicy{
+			Action: FatalExit,
+			Exit:   func(code int) { exited = true },
+		},
+	})
+
+	manager.HandlePanic(context.Background(), "boom")
+
+	if exited {
+		t.Fatal("Recovered panic terminated the process")
+	}
+}
+
+func TestFatalPolicy_Escalate(t *testing.T) {
+
+	escalation := &recordingObserver{}
+
+	manager := NewManager(ManagerConfig{
+		Logger: &mockLogger{},
+		Fatal: FatalPolicy{
+			Action:     FatalEscalate,
+			Escalation: escalation,
+		},
+	})
+
+	manager.Handle(context.Background(), errors.New("not fatal"))
+	manager.HandlePanic(context.Background(), "boom")
+
+	if len(escalation.observed) != 1 {
+		t.Fatalf("Expected 1 escalation, got %d", len(escalation.observed))
+	}
+}
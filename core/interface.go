@@ -10,6 +10,48 @@ type TraceProvider interface {
 	GetTraceID(ctx context.Context) string
 }
 
+// SpanProvider is optionally implemented by trace providers that also know the current span
+type SpanProvider interface {
+	GetSpanID(ctx context.Context) string
+}
+
+// ContextExtractor pulls request attributes such as user or tenant IDs from the context
+type ContextExtractor interface {
+	Extract(ctx context.Context) map[string]any
+}
+
+// ContextExtractorFunc adapts a function to ContextExtractor
+type ContextExtractorFunc func(ctx context.Context) map[string]any
+
+func (f ContextExtractorFunc) Extract(ctx context.Context) map[string]any {
+	return f(ctx)
+}
+
+// ContextValueExtractor extracts ctx.Value(key) as the attribute name
+func ContextValueExtractor(name string, key any) ContextExtractor {
+
+	return ContextExtractorFunc(func(ctx context.Context) map[string]any {
+
+		value := ctx.Value(key)
+
+		if value == nil {
+			return nil
+		}
+
+		return map[string]any{name: value}
+	})
+}
+
 type StackTraceProvider interface {
 	Capture() string
 }
+
+// Observer is notified once for every error the manager handles
+type Observer interface {
+	Observe(ctx context.Context, err *AppError)
+}
+
+// PanicObserver is optionally implemented by observers interested in recovered panics
+type PanicObserver interface {
+	ObservePanic(ctx context.Context, err *AppError, recovered any)
+}
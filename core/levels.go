@@ -1,5 +1,10 @@
 package core
 
+import (
+	"fmt"
+	"strings"
+)
+
 type ErrorLevel int
 
 const (
@@ -26,3 +31,22 @@ func (l ErrorLevel) String() string {
 		return "UNKNOWN"
 	}
 }
+
+// ParseLevel converts a level name such as "warn" to an ErrorLevel
+func ParseLevel(level string) (ErrorLevel, error) {
+
+	switch strings.ToLower(level) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	case "fatal":
+		return LevelFatal, nil
+	default:
+		return LevelError, fmt.Errorf("unknown error level %q", level)
+	}
+}
@@ -10,6 +10,13 @@ type Manager struct {
 	logger             Logger
 	traceProvider      TraceProvider
 	stackTraceProvider StackTraceProvider
+	observers          []Observer
+	extractors         []ContextExtractor
+	debug              *DebugPolicy
+	shaper             *ResponseShaper
+	renderer           Renderer
+	fatal              *fatalHandler
+	events             *EventBus
 }
 
 // ManagerConfig allows flexible initialization
@@ -17,6 +24,12 @@ type ManagerConfig struct {
 	Logger             Logger
 	TraceProvider      TraceProvider
 	StackTraceProvider StackTraceProvider
+	Observers          []Observer
+	ContextExtractors  []ContextExtractor
+	Debug              *DebugPolicy // nil never exposes internals
+	Response           ResponseConfig
+	Renderer           Renderer // defaults to a ResponseShaper built from Response
+	Fatal              FatalPolicy
 }
 
 // NewManager creates a new error manager
@@ -26,10 +39,24 @@ func NewManager(config ManagerConfig) *Manager {
 		panic("Logger is required for error manager")
 	}
 
+	shaper := NewResponseShaper(config.Response)
+
+	renderer := config.Renderer
+	if renderer == nil {
+		renderer = shaper
+	}
+
 	return &Manager{
 		logger:             config.Logger,
 		traceProvider:      config.TraceProvider,
 		stackTraceProvider: config.StackTraceProvider,
+		observers:          config.Observers,
+		extractors:         config.ContextExtractors,
+		debug:              config.Debug,
+		shaper:             shaper,
+		renderer:           renderer,
+		fatal:              newFatalHandler(config.Fatal),
+		events:             NewEventBus(),
 	}
 }
 
@@ -44,7 +71,10 @@ func (m *Manager) Handle(ctx context.Context, err error) *AppError {
 	if errors.As(err, &appErr) {
 
 		m.enrich(ctx, appErr)
-		m.logger.Log(appErr)
+
+		if m.logOnce(ctx, appErr, false) {
+			m.fatal.handle(ctx, appErr, false)
+		}
 
 		return appErr
 	}
@@ -60,21 +90,99 @@ func (m *Manager) Handle(ctx context.Context, err error) *AppError {
 		Build()
 
 	m.enrich(ctx, appErr)
-	m.logger.Log(appErr)
+
+	if m.logOnce(ctx, appErr, false) {
+		m.fatal.handle(ctx, appErr, false)
+	}
 
 	return appErr
 }
 
+// OnShutdown registers a hook run before the process exits under FatalExit
+func (m *Manager) OnShutdown(hook ShutdownHook) {
+	m.fatal.register(hook)
+}
+
+// logOnce logs the error unless it, or an error it wraps, was already logged.
+// It reports whether the error was logged.
+func (m *Manager) logOnce(ctx context.Context, err *AppError, panicked bool) bool {
+
+	if !err.markHandled() {
+		return false
+	}
+
+	m.logger.Log(err)
+
+	for _, observer := range m.observers {
+		observer.Observe(ctx, err)
+	}
+
+	m.events.Publish(ErrorEvent{
+		Error: err,
+		Panic: panicked,
+		Time:  time.Now(),
+	})
+
+	return true
+}
+
+// Report logs an informational event and publishes it to subscribers.
+// Observers are skipped, so reported events are not counted as failures.
+func (m *Manager) Report(ctx context.Context, event *AppError) {
+
+	m.enrich(ctx, event)
+
+	if !event.markHandled() {
+		return
+	}
+
+	m.logger.Log(event)
+
+	m.events.Publish(ErrorEvent{
+		Error: event,
+		Time:  time.Now(),
+	})
+}
+
+// Subscribe delivers handled errors matching filter until ctx is canceled
+func (m *Manager) Subscribe(ctx context.Context, filter EventFilter, buffer int) *Subscription {
+	return m.events.Subscribe(ctx, filter, buffer)
+}
+
 func (m *Manager) enrich(ctx context.Context, err *AppError) {
 
 	if err.Timestamp.IsZero() {
 		err.Timestamp = time.Now()
 	}
 
+	if err.Domain == "" {
+		err.Domain = DomainForCode(err.Code)
+	}
+
 	if m.traceProvider != nil && err.TraceID == "" {
 		err.TraceID = m.traceProvider.GetTraceID(ctx)
 	}
 
+	if spanProvider, ok := m.traceProvider.(SpanProvider); ok && err.SpanID == "" {
+		err.SpanID = spanProvider.GetSpanID(ctx)
+	}
+
+	if ctx != nil {
+		for _, extractor := range m.extractors {
+			for key, value := range extractor.Extract(ctx) {
+
+				if err.Attributes == nil {
+					err.Attributes = make(map[string]any)
+				}
+
+				// Attributes set explicitly on the error win
+				if _, ok := err.Attributes[key]; !ok {
+					err.Attributes[key] = value
+				}
+			}
+		}
+	}
+
 	if m.stackTraceProvider != nil && err.StackTrace == "" {
 		err.StackTrace = m.stackTraceProvider.Capture()
 	}
@@ -84,33 +192,105 @@ func (m *Manager) ToResponse(ctx context.Context, err error) *AppError {
 
 	appErr := m.Handle(ctx, err)
 
+	// Outside production the caller may see everything
+	if m.debug.Allowed(ctx) {
+		return &AppError{
+			Message:    appErr.Message,
+			Code:       appErr.Code,
+			Domain:     appErr.Domain,
+			Status:     appErr.Status,
+			Details:    appErr.Details,
+			Err:        appErr.Err,
+			TraceID:    appErr.TraceID,
+			Timestamp:  appErr.Timestamp,
+			StackTrace: appErr.StackTrace,
+		}
+	}
+
 	// Return sanitized copy
 	return &AppError{
 		Message:   appErr.SafeMessage(),
 		Code:      appErr.SafeCode(),
 		Status:    appErr.Status,
+		Details:   m.shaper.PublicDetails(appErr),
 		TraceID:   appErr.TraceID,
 		Timestamp: appErr.Timestamp,
 	}
 }
 
+// Respond handles err and returns the HTTP status and the body built by the manager's renderer.
+// Adapters render through the same path, so naming, fields and envelope match everywhere.
+func (m *Manager) Respond(ctx context.Context, err error) (int, any) {
+
+	appErr := m.Handle(ctx, err)
+
+	if appErr == nil {
+		return 0, nil
+	}
+
+	return appErr.Status, m.Render(ctx, appErr, nil)
+}
+
+// Render builds the public response body for a handled error
+func (m *Manager) Render(ctx context.Context, err *AppError, warnings []Warning) any {
+
+	return m.renderer.Render(ctx, ResponseData{
+		Error:    err,
+		Details:  m.shaper.PublicDetails(err),
+		Debug:    m.DebugInfo(ctx, err),
+		Warnings: warnings,
+	})
+}
+
+// DebugInfo returns the internals of err when the debug policy allows it for ctx, nil otherwise
+func (m *Manager) DebugInfo(ctx context.Context, err *AppError) *DebugInfo {
+
+	if err == nil || !m.debug.Allowed(ctx) {
+		return nil
+	}
+
+	return NewDebugInfo(err)
+}
+
 func (m *Manager) HandlePanic(ctx context.Context, recovered any) *AppError {
+	return m.HandlePanicWithStack(ctx, recovered, "")
+}
 
-	appErr := New().
+// HandlePanicWithStack handles a recovered panic using the stack captured at the panic site
+func (m *Manager) HandlePanicWithStack(ctx context.Context, recovered any, stack string) *AppError {
+
+	appErr := NewPanicError(recovered, stack)
+
+	m.enrich(ctx, appErr)
+	m.logOnce(ctx, appErr, true)
+
+	for _, observer := range m.observers {
+		if panicObserver, ok := observer.(PanicObserver); ok {
+			panicObserver.ObservePanic(ctx, appErr, recovered)
+		}
+	}
+
+	m.fatal.handle(ctx, appErr, true)
+
+	return appErr
+}
+
+// NewPanicError converts a recovered panic value to an AppError
+func NewPanicError(recovered any, stack string) *AppError {
+
+	return New().
 		WithMessage("Internal server error").
 		WithCode(CodeInternalError).
 		WithStatus(500).
 		WithLevel(LevelFatal).
 		WithSensitive(true).
 		WithDetail("panic", recovered).
+		WithStackTrace(stack).
 		Build()
-
-	m.enrich(ctx, appErr)
-	m.logger.Log(appErr)
-
-	return appErr
 }
 
+// Wrap adds context to an error without logging it.
+// Logging is deferred to Handle so the log entry carries every wrapping layer.
 func (m *Manager) Wrap(ctx context.Context, err error, message string) *AppError {
 
 	if err == nil {
@@ -126,7 +306,47 @@ func (m *Manager) Wrap(ctx context.Context, err error, message string) *AppError
 		Build()
 
 	m.enrich(ctx, appErr)
-	m.logger.Log(appErr)
 
 	return appErr
 }
+
+// FlushCollected logs the soft errors and warnings collected for the request.
+// Each entry is enriched from ctx, so it carries the request's trace ID.
+// Warnings are only logged: observers, metrics and alerting never see them.
+func (m *Manager) FlushCollected(ctx context.Context) []*AppError {
+
+	collector := CollectorFromContext(ctx)
+
+	if collector == nil {
+		return nil
+	}
+
+	warnings, errs := collector.drain()
+
+	var handled []*AppError
+
+	for _, warning := range warnings {
+
+		appErr := New().
+			WithMessage(warning.Message).
+			WithCode(warning.Code).
+			WithStatus(200).
+			WithLevel(LevelWarn).
+			WithSensitive(false).
+			Build()
+
+		m.enrich(ctx, appErr)
+
+		if appErr.markHandled() {
+			m.logger.Log(appErr)
+		}
+
+		handled = append(handled, appErr)
+	}
+
+	for _, err := range errs {
+		handled = append(handled, m.Handle(ctx, err))
+	}
+
+	return handled
+}
@@ -1,17 +1,26 @@
 package core
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 )
 
 type mockLogger struct {
 	called bool
+	count  int
+	last   *AppError
 }
 
 func (m *mockLogger) Log(err *AppError) {
 	m.called = true
+	m.count++
+	m.last = err
 }
+
 func TestManager_Handle_AppError(t *testing.T) {
 
 	logger := &mockLogger{}
@@ -49,3 +58,93 @@ func TestManager_Handle_UnknownError(t *testing.T) {
 		t.Fatal("Did not convert to internal error")
 	}
 }
+
+func TestManager_Handle_LogsOnce(t *testing.T) {
+
+	logger := &mockLogger{}
+
+	manager := NewManager(ManagerConfig{
+		Logger: logger,
+	})
+
+	err := New().
+		WithMessage("test").
+		Build()
+
+	manager.Handle(nil, err)
+	manager.Handle(nil, err)
+
+	if logger.count != 1 {
+		t.Fatalf("Expected 1 log entry for the same instance, got %d", logger.count)
+	}
+}
+
+func TestManager_Handle_WrapOfHandledErrorLogsOnce(t *testing.T) {
+
+	logger := &mockLogger{}
+
+	manager := NewManager(ManagerConfig{
+		Logger: logger,
+	})
+
+	inner := manager.Handle(nil, errors.New("root"))
+
+	wrapped := manager.Wrap(nil, fmt.Errorf("loading order: %w", inner), "failed")
+
+	manager.Handle(nil, wrapped)
+
+	if logger.count != 1 {
+		t.Fatalf("Expected 1 log entry for the same failure, got %d", logger.count)
+	}
+
+	if logger.last != inner || !wrapped.IsHandled() {
+		t.Fatal("Wrapper of a handled error must not be logged again")
+	}
+}
+
+func TestManager_Wrap_DefersLogging(t *testing.T) {
+
+	logger := &mockLogger{}
+
+	manager := NewManager(ManagerConfig{
+		Logger: logger,
+	})
+
+	wrapped := manager.Wrap(nil, errors.New("root"), "failed")
+
+	if logger.called {
+		t.Fatal("Wrap should not log")
+	}
+
+	manager.Handle(nil, wrapped)
+
+	if logger.count != 1 {
+		t.Fatalf("Expected 1 log entry, got %d", logger.count)
+	}
+}
+
+type tenantKey struct{}
+
+func TestManager_ContextExtractors(t *testing.T) {
+
+	manager := NewManager(ManagerConfig{
+		Logger: &mockLogger{},
+		ContextExtractors: []ContextExtractor{
+			ContextValueExtractor("tenant_id", tenantKey{}),
+		},
+	})
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+
+	appErr := manager.Handle(ctx, New().WithSensitive(false).Build())
+
+	if appErr.Attributes["tenant_id"] != "acme" {
+		t.Fatal("Attribute not extracted")
+	}
+
+	body, _ := json.Marshal(manager.Render(ctx, appErr, nil))
+
+	if strings.Contains(string(body), "acme") {
+		t.Fatal("Attributes leaked into response")
+	}
+}
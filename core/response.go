@@ -0,0 +1,177 @@
+package core
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// Response field names, in snake case
+const (
+	FieldMessage   = "message"
+	FieldCode      = "code"
+	FieldStatus    = "status"
+	FieldTraceID   = "trace_id"
+	FieldDetails   = "details"
+	FieldTimestamp = "timestamp"
+	FieldDebug     = "debug"
+	FieldWarnings  = "warnings"
+)
+
+var defaultFields = []string{FieldMessage, FieldCode, FieldStatus, FieldTraceID, FieldDetails}
+
+type FieldNaming int
+
+const (
+	NamingSnake FieldNaming = iota
+	NamingCamel
+)
+
+// ResponseData is everything a renderer may expose about a handled error
+type ResponseData struct {
+	Error    *AppError
+	Details  map[string]any // details cleared for the client
+	Debug    *DebugInfo     // nil unless the debug policy allows it
+	Warnings []Warning
+}
+
+// Renderer turns a handled error into the public response body
+type Renderer interface {
+	Render(ctx context.Context, data ResponseData) any
+}
+
+type ResponseConfig struct {
+	Naming FieldNaming
+	Fields []string // defaults to message, code, status, trace_id and details
+
+	// Envelope wraps the body in an object under this key, e.g. {"error": {...}}
+	Envelope string
+
+	// DetailsAllowlist limits the detail keys exposed for a code.
+	// Codes without an entry expose all details unless StrictDetails is set.
+	DetailsAllowlist map[string][]string
+	StrictDetails    bool
+}
+
+// ResponseShaper is the default Renderer
+type ResponseShaper struct {
+	config ResponseConfig
+}
+
+func NewResponseShaper(config ResponseConfig) *ResponseShaper {
+
+	if len(config.Fields) == 0 {
+		config.Fields = defaultFields
+	}
+
+	return &ResponseShaper{
+		config: config,
+	}
+}
+
+// PublicDetails returns the details of err that may be shown to clients
+func (s *ResponseShaper) PublicDetails(err *AppError) map[string]any {
+
+	if err.IsSensitive || len(err.Details) == 0 {
+		return nil
+	}
+
+	allowed, ok := s.config.DetailsAllowlist[err.Code]
+
+	if !ok {
+		if s.config.StrictDetails {
+			return nil
+		}
+
+		return err.Details
+	}
+
+	details := make(map[string]any)
+
+	for key, value := range err.Details {
+		if slices.Contains(allowed, key) {
+			details[key] = value
+		}
+	}
+
+	if len(details) == 0 {
+		return nil
+	}
+
+	return details
+}
+
+func (s *ResponseShaper) Render(ctx context.Context, data ResponseData) any {
+
+	err := data.Error
+
+	body := make(map[string]any)
+
+	for _, field := range s.config.Fields {
+
+		switch field {
+		case FieldMessage:
+			body[s.name(field)] = err.SafeMessage()
+		case FieldCode:
+			body[s.name(field)] = err.SafeCode()
+		case FieldStatus:
+			body[s.name(field)] = err.Status
+		case FieldTraceID:
+			body[s.name(field)] = err.TraceID
+		case FieldTimestamp:
+			body[s.name(field)] = err.Timestamp
+		case FieldDetails:
+			if len(data.Details) > 0 {
+				body[s.name(field)] = data.Details
+			}
+		}
+	}
+
+	if data.Debug != nil {
+		body[s.name(FieldDebug)] = data.Debug
+	}
+
+	if len(data.Warnings) > 0 {
+		body[s.name(FieldWarnings)] = data.Warnings
+	}
+
+	if s.config.Envelope != "" {
+		return map[string]any{s.config.Envelope: body}
+	}
+
+	return body
+}
+
+// UnwrapEnvelope returns the inner object of decoded bodies like {"error": {...}}.
+// Bodies with a top-level code, or more than one member, are returned as they are.
+func UnwrapEnvelope(body map[string]any) map[string]any {
+
+	if _, ok := body[FieldCode]; ok || len(body) != 1 {
+		return body
+	}
+
+	for _, value := range body {
+		if inner, ok := value.(map[string]any); ok {
+			return inner
+		}
+	}
+
+	return body
+}
+
+func (s *ResponseShaper) name(field string) string {
+
+	if s.config.Naming != NamingCamel {
+		return field
+	}
+
+	parts := strings.Split(field, "_")
+
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"context"
+	"testing"
+)
+
+func TestResponseShaper_Defaults(t *testing.T) {
+
+	shaper := NewResponseShaper(ResponseConfig{})
+
+	err := New().WithMessage("secret").WithDetail("table", "users").Build()
+
+	body := shaper.Render(context.Background(), ResponseData{
+		Error:   err,
+		Details: shaper.PublicDetails(err),
+	}).(map[string]any)
+
+	if body["message"] != "Internal server error" {
+		t.Fatal("Sensitive message leaked")
+	}
+
+	if _, ok := body["details"]; ok {
+		t.Fatal("Sensitive details leaked")
+	}
+}
+
+func TestResponseShaper_CamelEnvelope(t *testing.T) {
+
+	shaper := NewResponseShaper(ResponseConfig{
+		Naming:   NamingCamel,
+		Envelope: "error",
+	})
+
+	body := shaper.Render(context.Background(), ResponseData{
+		Error: New().WithTraceID("t-1").Build(),
+	}).(map[string]any)
+
+	inner, ok := body["error"].(map[string]any)
+
+	if !ok {
+		t.Fatal("Envelope missing")
+	}
+
+	if inner["traceId"] != "t-1" {
+		t.Fatal("Field not renamed to camel case")
+	}
+}
+
+func TestResponseShaper_DetailsAllowlist(t *testing.T) {
+
+	shaper := NewResponseShaper(ResponseConfig{
+		DetailsAllowlist: map[string][]string{
+			CodeDBDuplicateKey: {"constraint"},
+		},
+	})
+
+	err := New().
+		WithCode(CodeDBDuplicateKey).
+		WithSensitive(false).
+		WithDetail("constraint", "users_email_key").
+		WithDetail("table", "users").
+		Build()
+
+	details := shaper.PublicDetails(err)
+
+	if len(details) != 1 || details["constraint"] != "users_email_key" {
+		t.Fatalf("Unexpected details: %v", details)
+	}
+}
+
+func TestManager_ToResponse_IncludesPublicDetails(t *testing.T) {
+
+	manager := NewManager(ManagerConfig{
+		Logger: &mockLogger{},
+	})
+
+	err := New().WithSensitive(false).WithDetail("email", "is required").Build()
+
+	if manager.ToResponse(context.Background(), err).Details["email"] != "is required" {
+		t.Fatal("Public details dropped")
+	}
+
+	_, body := manager.Respond(context.Background(), err)
+
+	details, _ := body.(map[string]any)[FieldDetails].(map[string]any)
+
+	if details["email"] != "is required" {
+		t.Fatal("Public details not rendered")
+	}
+}
+
+func TestUnwrapEnvelope(t *testing.T) {
+
+	inner := map[string]any{FieldCode: CodeNotFound}
+
+	if UnwrapEnvelope(map[string]any{"error": inner})[FieldCode] != CodeNotFound {
+		t.Fatal("Envelope not unwrapped")
+	}
+
+	if UnwrapEnvelope(inner)[FieldCode] != CodeNotFound {
+		t.Fatal("Unwrapped body changed")
+	}
+}
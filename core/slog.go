@@ -0,0 +1,69 @@
+package core
+
+import (
+	"log/slog"
+	"sort"
+)
+
+// LogValue implements slog.LogValuer so AppErrors are logged as structured groups
+func (e *AppError) LogValue() slog.Value {
+
+	attrs := []slog.Attr{
+		slog.String("message", e.Message),
+		slog.String("code", e.Code),
+		slog.Int("status", e.Status),
+		slog.String("level", e.Level.String()),
+	}
+
+	if e.Domain != "" {
+		attrs = append(attrs, slog.String("domain", e.Domain))
+	}
+
+	if e.TraceID != "" {
+		attrs = append(attrs, slog.String("trace_id", e.TraceID))
+	}
+
+	if e.SpanID != "" {
+		attrs = append(attrs, slog.String("span_id", e.SpanID))
+	}
+
+	if !e.Timestamp.IsZero() {
+		attrs = append(attrs, slog.Time("timestamp", e.Timestamp))
+	}
+
+	if len(e.Details) > 0 {
+		attrs = append(attrs, mapGroup("details", e.Details))
+	}
+
+	if len(e.Attributes) > 0 {
+		attrs = append(attrs, mapGroup("attributes", e.Attributes))
+	}
+
+	if causes := e.Causes(); len(causes) > 0 {
+		attrs = append(attrs, slog.Any("causes", causes))
+	}
+
+	if e.StackTrace != "" {
+		attrs = append(attrs, slog.String("stacktrace", e.StackTrace))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// mapGroup converts a map to a group with keys in a stable order
+func mapGroup(name string, values map[string]any) slog.Attr {
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	attrs := make([]any, 0, len(keys))
+	for _, key := range keys {
+		attrs = append(attrs, slog.Any(key, values[key]))
+	}
+
+	return slog.Group(name, attrs...)
+}
@@ -1,14 +1,16 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"net/http"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	echoadapter "github.com/krisalay/error-framework/adapters/echo"
 	validatoradapter "github.com/krisalay/error-framework/adapters/validator"
 	"github.com/krisalay/error-framework/errorframework/config"
 	"github.com/krisalay/error-framework/errorframework/framework"
-	"github.com/krisalay/error-framework/utils"
 	"github.com/labstack/echo/v4"
 )
 
@@ -50,7 +52,8 @@ func main() {
 		panic(err)
 	}
 
-	traceProvider := utils.NewTraceProvider()
+	// Uses the configured trace ID generator
+	traceProvider := framework.Default().TraceProvider()
 
 	// Echo setup
 	e := echo.New()
@@ -82,7 +85,7 @@ func registerRoutes(e *echo.Echo) {
 
 	e.GET("/custom-error", customErrorHandler)
 
-	// e.GET("/goroutine-panic", goroutinePanicHandler)
+	e.GET("/goroutine-panic", goroutinePanicHandler)
 }
 
 // SUCCESS DEMO
@@ -148,21 +151,18 @@ func customErrorHandler(c echo.Context) error {
 }
 
 // GOROUTINE PANIC DEMO
-// func goroutinePanicHandler(c echo.Context) error {
+func goroutinePanicHandler(c echo.Context) error {
 
-// 	ctx := c.Request().Context()
+	ctx := c.Request().Context()
 
-// 	go func() {
+	framework.Go(ctx, func(ctx context.Context) error {
 
-// 		defer framework.Recover(ctx)
+		time.Sleep(1 * time.Second)
 
-// 		time.Sleep(1 * time.Second)
-
-// 		panic("goroutine panic demo")
-
-// 	}()
+		panic("goroutine panic demo")
+	})
 
-// 	return c.JSON(http.StatusOK, map[string]string{
-// 		"message": "goroutine started",
-// 	})
-// }
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "goroutine started",
+	})
+}
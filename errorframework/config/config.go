@@ -1,8 +1,17 @@
 package config
 
+import "time"
+
 type Config struct {
+	Environment string // dev, staging or prod (default)
+
+	Debug DebugConfig
+
 	Logger LoggerConfig
 
+	// Sinks receive errors in addition to Logger
+	Sinks []SinkConfig
+
 	Trace TraceConfig
 
 	StackTrace StackTraceConfig
@@ -10,18 +19,84 @@ type Config struct {
 	Database DatabaseConfig
 
 	Validator ValidatorConfig
+
+	Response ResponseConfig
+
+	Fatal FatalConfig
 }
 
 type LoggerConfig struct {
+	Backend        string // zap (default) or slog
 	ConsoleEnabled bool
 	FileEnabled    bool
 	FilePath       string
 	Level          string
 	Encoding       string // json or console
+	Schema         string // "" (default), ecs or otel; zap backend only
+
+	// File rotation
+	MaxSizeMB      int
+	RotateInterval time.Duration
+	MaxAge         time.Duration
+	MaxBackups     int
+	Compress       bool
+	ReopenOnSIGHUP bool
+
+	// Syslog (RFC 5424), zap backend only
+	SyslogEnabled bool
+	SyslogNetwork string // unixgram, unix, udp or tcp; empty uses the local daemon
+	SyslogAddress string
+	SyslogAppName string
+}
+
+type DebugConfig struct {
+	Secret string // verifies debug tokens in staging
+
+	// AllowedCIDRs restricts debug tokens to clients in these networks, e.g. "10.0.0.0/8".
+	// Empty accepts valid tokens from any client.
+	AllowedCIDRs []string
+}
+
+type SinkConfig struct {
+	Name   string
+	Logger LoggerConfig
+	Filter SinkFilterConfig
+	Async  bool // write through a bounded queue so a slow sink does not delay the others
+
+	// Queue behavior when Async is set
+	Overflow     string        // drop_oldest (default), drop_newest or block
+	BlockTimeout time.Duration // bounds the wait of block, 0 waits indefinitely
+}
+
+type SinkFilterConfig struct {
+	MinLevel  string // debug, info, warn, error, fatal
+	Codes     []string
+	MinStatus int
+	MaxStatus int
+	Domains   []string
+}
+
+type ResponseConfig struct {
+	Naming           string   // snake (default) or camel
+	Fields           []string // message, code, status, trace_id, details, timestamp
+	Envelope         string   // wraps the body, e.g. "error"
+	DetailsAllowlist map[string][]string
+	StrictDetails    bool
+}
+
+type FatalConfig struct {
+	Action          string // log (default), exit or escalate
+	ShutdownTimeout time.Duration
+	ExitOnPanic     bool
+
+	// EscalationWebhook receives fatal errors as alerts, required by the escalate action
+	EscalationWebhook string
 }
 
 type TraceConfig struct {
-	Enabled bool
+	Enabled   bool
+	Provider  string // uuid or otel
+	Generator string // uuidv4 (default), uuidv7, ulid or w3c
 }
 
 type StackTraceConfig struct {
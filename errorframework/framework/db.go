@@ -2,13 +2,19 @@ package framework
 
 import "github.com/krisalay/error-framework/core"
 
-func DB(err error) *core.AppError {
+func (f *Framework) DB(err error) *core.AppError {
 
 	if err == nil {
 		return nil
 	}
 
-	f := get()
+	if f.dbAdapter == nil {
+		return f.Internal(err)
+	}
 
 	return f.dbAdapter.FromError(err)
 }
+
+func DB(err error) *core.AppError {
+	return Default().DB(err)
+}
@@ -1,42 +1,144 @@
 package framework
 
 import (
-	"sync"
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
 
 	pgxadapter "github.com/krisalay/error-framework/adapters/pgx"
 	validatoradapter "github.com/krisalay/error-framework/adapters/validator"
+	"github.com/krisalay/error-framework/alerting"
 	"github.com/krisalay/error-framework/core"
+	"github.com/krisalay/error-framework/logging"
+	"github.com/krisalay/error-framework/utils"
 )
 
 type Framework struct {
 	manager          *core.Manager
 	dbAdapter        *pgxadapter.Adapter
 	validatorAdapter *validatoradapter.Adapter
+	levels           *logging.LevelController
+	logger           core.Logger
+	closers          []io.Closer
+	escalation       *alerting.Engine
+	traceProvider    *utils.TraceProvider
 }
 
-var instance *Framework
-var once sync.Once
+type contextKey struct{}
 
+var defaultInstance atomic.Pointer[Framework]
+
+// New creates a framework instance.
+// Without an adapter, DB or Validation convert errors with Internal.
+func New(
+	manager *core.Manager,
+	dbAdapter *pgxadapter.Adapter,
+	validatorAdapter *validatoradapter.Adapter,
+) *Framework {
+
+	return &Framework{
+		manager:          manager,
+		dbAdapter:        dbAdapter,
+		validatorAdapter: validatorAdapter,
+	}
+}
+
+// Init replaces the default instance used by the package-level functions
 func Init(
 	manager *core.Manager,
 	dbAdapter *pgxadapter.Adapter,
 	validatorAdapter *validatoradapter.Adapter,
 ) {
+	SetDefault(New(manager, dbAdapter, validatorAdapter))
+}
+
+// Default returns the default instance, creating one without a manager if none is set
+func Default() *Framework {
+
+	if f := defaultInstance.Load(); f != nil {
+		return f
+	}
+
+	defaultInstance.CompareAndSwap(nil, New(nil, nil, nil))
+
+	return defaultInstance.Load()
+}
+
+func SetDefault(f *Framework) {
+	defaultInstance.Store(f)
+}
+
+// Reset clears the default instance, mainly for tests
+func Reset() {
+	defaultInstance.Store(nil)
+}
+
+// WithContext attaches a framework instance to the context
+func WithContext(ctx context.Context, f *Framework) context.Context {
+	return context.WithValue(ctx, contextKey{}, f)
+}
 
-	once.Do(func() {
-		instance = &Framework{
-			manager:          manager,
-			dbAdapter:        dbAdapter,
-			validatorAdapter: validatorAdapter,
+// FromContext returns the instance attached to ctx, or the default instance
+func FromContext(ctx context.Context) *Framework {
+
+	if ctx != nil {
+		if f, ok := ctx.Value(contextKey{}).(*Framework); ok && f != nil {
+			return f
 		}
-	})
+	}
+
+	return Default()
+}
+
+func (f *Framework) Manager() *core.Manager {
+	return f.manager
+}
+
+// TraceProvider returns the trace provider built from the config, for TraceMiddleware
+// and outbound transports, so they create trace IDs with the configured generator.
+// Instances without tracing get a default provider.
+func (f *Framework) TraceProvider() *utils.TraceProvider {
+
+	if f.traceProvider == nil {
+		return utils.NewTraceProvider()
+	}
+
+	return f.traceProvider
 }
 
-func get() *Framework {
+// LogLevels returns the runtime level controller of the configured logger.
+// It is nil unless the instance was built by NewFromConfig.
+func (f *Framework) LogLevels() *logging.LevelController {
+	return f.levels
+}
+
+// Close delivers pending fatal escalations, flushes queued log entries and closes
+// the log files and connections opened by NewFromConfig. Entries logged afterwards may be lost.
+func (f *Framework) Close(ctx context.Context) error {
+
+	var errs []error
+
+	if f.escalation != nil {
+		errs = append(errs, f.escalation.Close(ctx))
+	}
 
-	if instance == nil {
-		panic("framework not initialized")
+	if shutdowner, ok := f.logger.(interface {
+		Shutdown(ctx context.Context) error
+	}); ok {
+		errs = append(errs, shutdowner.Shutdown(ctx))
 	}
 
-	return instance
+	for _, closer := range f.closers {
+		errs = append(errs, closer.Close())
+	}
+
+	f.closers = nil
+
+	return errors.Join(errs...)
+}
+
+// Close closes the default instance
+func Close(ctx context.Context) error {
+	return Default().Close(ctx)
 }
@@ -0,0 +1,220 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	echoadapter "github.com/krisalay/error-framework/adapters/echo"
+	"github.com/krisalay/error-framework/core"
+	"github.com/krisalay/error-framework/errorframework/config"
+	"github.com/krisalay/error-framework/utils"
+	"github.com/labstack/echo/v4"
+)
+
+func TestDefault_WithoutInit(t *testing.T) {
+
+	Reset()
+
+	appErr := DB(errors.New("connection refused"))
+
+	if appErr.Code != core.CodeInternalError {
+		t.Fatal("DB without an adapter should fall back to Internal")
+	}
+}
+
+func TestNewFromConfig_AdaptersFromConfig(t *testing.T) {
+
+	f, err := NewFromConfig(config.Config{
+		Database:  config.DatabaseConfig{Type: "pgx"},
+		Validator: config.ValidatorConfig{Enabled: false},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f.DB(errors.New("connection refused")).Code != core.CodeDBError {
+		t.Fatal("Configured pgx adapter not used")
+	}
+
+	if f.Validation(errors.New("bad input")).Code != core.CodeInternalError {
+		t.Fatal("Disabled validator should fall back to Internal")
+	}
+}
+
+func TestInit_Reinitializes(t *testing.T) {
+
+	defer Reset()
+
+	initTestFramework()
+	first := Default()
+
+	initTestFramework()
+
+	if Default() == first {
+		t.Fatal("Init did not replace the default instance")
+	}
+}
+
+func TestFromContext(t *testing.T) {
+
+	defer Reset()
+
+	f := New(nil, nil, nil)
+
+	ctx := WithContext(context.Background(), f)
+
+	if FromContext(ctx) != f {
+		t.Fatal("Instance not taken from context")
+	}
+
+	if FromContext(context.Background()) != Default() {
+		t.Fatal("Default instance not used without context instance")
+	}
+}
+
+func TestClose_FlushesAsyncSinks(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	f, err := NewFromConfig(config.Config{
+		Logger: config.LoggerConfig{Level: "fatal"},
+		Sinks: []config.SinkConfig{{
+			Name:   "audit",
+			Logger: config.LoggerConfig{FileEnabled: true, FilePath: path, Level: "debug"},
+			Async:  true,
+		}},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.Manager().Handle(context.Background(), core.New().WithCode(core.CodeForbidden).Build())
+
+	if err := f.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(content), core.CodeForbidden) {
+		t.Fatal("Queued entry lost on Close")
+	}
+}
+
+func TestNewFromConfig_UnknownOverflow(t *testing.T) {
+
+	_, err := NewFromConfig(config.Config{
+		Sinks: []config.SinkConfig{{Name: "audit", Async: true, Overflow: "spill"}},
+	})
+
+	if err == nil {
+		t.Fatal("Expected an error for an unknown overflow policy")
+	}
+}
+
+func TestNewFromConfig_UnknownGeneratorOpensNothing(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	_, err := NewFromConfig(config.Config{
+		Logger: config.LoggerConfig{FileEnabled: true, FilePath: path},
+		Trace:  config.TraceConfig{Enabled: true, Generator: "snowflake"},
+	})
+
+	if err == nil {
+		t.Fatal("Expected an error for an unknown trace ID generator")
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("Log file opened before the configuration was validated")
+	}
+}
+
+func TestNewFromConfig_InvalidDebugCIDR(t *testing.T) {
+
+	_, err := NewFromConfig(config.Config{
+		Environment: "staging",
+		Debug:       config.DebugConfig{Secret: "secret", AllowedCIDRs: []string{"10.0.0.1"}},
+	})
+
+	if err == nil {
+		t.Fatal("Expected an error for a CIDR without prefix length")
+	}
+}
+
+func TestNewFromConfig_FatalEscalate(t *testing.T) {
+
+	received := make(chan struct{}, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+	}))
+	defer server.Close()
+
+	f, err := NewFromConfig(config.Config{
+		Logger: config.LoggerConfig{Level: "fatal"},
+		Fatal:  config.FatalConfig{Action: "escalate", EscalationWebhook: server.URL},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.Manager().Handle(context.Background(), core.New().WithLevel(core.LevelFatal).Build())
+
+	if err := f.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-received:
+	default:
+		t.Fatal("Fatal error not escalated")
+	}
+}
+
+func TestNewFromConfig_EscalateRequiresWebhook(t *testing.T) {
+
+	if _, err := NewFromConfig(config.Config{Fatal: config.FatalConfig{Action: "escalate"}}); err == nil {
+		t.Fatal("Expected an error for escalate without a webhook")
+	}
+}
+
+func TestTraceProvider_UsesConfiguredGenerator(t *testing.T) {
+
+	f, err := NewFromConfig(config.Config{
+		Trace: config.TraceConfig{Enabled: true, Generator: "uuidv7"},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := echo.New()
+	e.Use(echoadapter.TraceMiddleware(f.TraceProvider()))
+	e.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	traceID := rec.Header().Get(utils.HeaderTraceID)
+
+	// The version nibble of a UUIDv7
+	if len(traceID) != 36 || traceID[14] != '7' {
+		t.Fatalf("Middleware did not use the configured generator: %q", traceID)
+	}
+}
@@ -1,24 +1,72 @@
 package framework
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+
+	oteladapter "github.com/krisalay/error-framework/adapters/otel"
 	pgxadapter "github.com/krisalay/error-framework/adapters/pgx"
 	validatoradapter "github.com/krisalay/error-framework/adapters/validator"
+	"github.com/krisalay/error-framework/alerting"
 	"github.com/krisalay/error-framework/core"
 	"github.com/krisalay/error-framework/errorframework/config"
 	"github.com/krisalay/error-framework/logging"
 	"github.com/krisalay/error-framework/utils"
 )
 
+// InitFromConfig builds a framework from cfg and makes it the default instance
 func InitFromConfig(cfg config.Config) (*core.Manager, error) {
 
+	f, err := NewFromConfig(cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	SetDefault(f)
+
+	return f.manager, nil
+}
+
+// NewFromConfig builds a framework instance from cfg without touching the default instance
+func NewFromConfig(cfg config.Config) (*Framework, error) {
+
+	// Debug policy
+	debugPolicy, err := newDebugPolicy(cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Fatal policy
+	fatalPolicy, err := newFatalPolicy(cfg.Fatal)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Trace ID generator, resolved before the logger opens files and connections
+	var generator utils.IDGenerator
+
+	if cfg.Trace.Enabled {
+
+		generator, err = newTraceIDGenerator(cfg.Trace.Generator)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Logger
-	logger, err := logging.NewZapLogger(logging.Config{
-		ConsoleEnabled: cfg.Logger.ConsoleEnabled,
-		FileEnabled:    cfg.Logger.FileEnabled,
-		FilePath:       cfg.Logger.FilePath,
-		Level:          cfg.Logger.Level,
-		Encoding:       cfg.Logger.Encoding,
-	})
+	levels, err := newLevelController(cfg.Logger.Level)
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger, closers, err := newLogger(cfg, levels)
 
 	if err != nil {
 		return nil, err
@@ -26,9 +74,26 @@ func InitFromConfig(cfg config.Config) (*core.Manager, error) {
 
 	// Trace provider
 	var traceProvider core.TraceProvider
+	var idProvider *utils.TraceProvider
+	var observers []core.Observer
 
 	if cfg.Trace.Enabled {
-		traceProvider = utils.NewTraceProvider()
+
+		idProvider = utils.NewTraceProvider().WithGenerator(generator)
+		traceProvider = idProvider
+
+		if cfg.Trace.Provider == "otel" {
+			traceProvider = oteladapter.NewTraceProvider().WithFallback(idProvider)
+			observers = append(observers, oteladapter.NewSpanRecorder())
+		}
+	}
+
+	// Escalation engine, started once nothing else can fail
+	var escalation *alerting.Engine
+
+	if fatalPolicy.Action == core.FatalEscalate {
+		escalation = newEscalationEngine(cfg.Fatal)
+		fatalPolicy.Escalation = escalation
 	}
 
 	// Stack trace provider
@@ -43,6 +108,10 @@ func InitFromConfig(cfg config.Config) (*core.Manager, error) {
 		Logger:             logger,
 		TraceProvider:      traceProvider,
 		StackTraceProvider: stackProvider,
+		Observers:          observers,
+		Debug:              debugPolicy,
+		Response:           newResponseConfig(cfg.Response),
+		Fatal:              fatalPolicy,
 	})
 
 	// DB Adapter
@@ -62,8 +131,272 @@ func InitFromConfig(cfg config.Config) (*core.Manager, error) {
 		validatorAdapter = validatoradapter.New()
 	}
 
-	// Framework initialization
-	Init(manager, dbAdapter, validatorAdapter)
+	f := New(manager, dbAdapter, validatorAdapter)
+	f.levels = levels
+	f.logger = logger
+	f.closers = closers
+	f.escalation = escalation
+	f.traceProvider = idProvider
+
+	return f, nil
+}
+
+func newDebugPolicy(cfg config.Config) (*core.DebugPolicy, error) {
+
+	policy, err := core.NewDebugPolicy(core.Environment(cfg.Environment), cfg.Debug.Secret)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cidr := range cfg.Debug.AllowedCIDRs {
+
+		network, err := netip.ParsePrefix(cidr)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid debug CIDR %q: %w", cidr, err)
+		}
+
+		policy.WithAllowedNetworks(network)
+	}
+
+	return policy, nil
+}
+
+func newTraceIDGenerator(name string) (utils.IDGenerator, error) {
+
+	switch name {
+	case "", "uuidv4":
+		return utils.UUIDv4Generator{}, nil
+	case "uuidv7":
+		return utils.UUIDv7Generator{}, nil
+	case "ulid":
+		return utils.ULIDGenerator{}, nil
+	case "w3c":
+		return utils.W3CGenerator{}, nil
+	default:
+		return nil, fmt.Errorf("unknown trace ID generator %q", name)
+	}
+}
+
+func newLevelController(level string) (*logging.LevelController, error) {
+
+	if level == "" {
+		return logging.NewLevelController(core.LevelError), nil
+	}
+
+	parsed, err := core.ParseLevel(level)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return logging.NewLevelController(parsed), nil
+}
+
+// newLogger builds the configured logger and returns the backends Close must release
+func newLogger(cfg config.Config, levels *logging.LevelController) (core.Logger, []io.Closer, error) {
+
+	var closers []io.Closer
+
+	logger, err := newBackendLogger(cfg.Logger, levels)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	closers = append(closers, logger)
+
+	if len(cfg.Sinks) == 0 {
+		return logger, closers, nil
+	}
+
+	sinks := []logging.Sink{
+		{Name: "default", Logger: logger},
+	}
+
+	for _, sinkCfg := range cfg.Sinks {
+
+		sinkLogger, err := newBackendLogger(sinkCfg.Logger, nil)
+
+		if err != nil {
+			closeAll(closers)
+			return nil, nil, err
+		}
+
+		closers = append(closers, sinkLogger)
+
+		filter, err := newFilter(sinkCfg.Filter)
+
+		if err != nil {
+			closeAll(closers)
+			return nil, nil, fmt.Errorf("sink %s: %w", sinkCfg.Name, err)
+		}
+
+		var sink core.Logger = sinkLogger
+
+		if sinkCfg.Async {
+
+			asyncConfig, err := newAsyncConfig(sinkCfg)
+
+			if err != nil {
+				closeAll(closers)
+				return nil, nil, fmt.Errorf("sink %s: %w", sinkCfg.Name, err)
+			}
+
+			sink = logging.NewAsyncLogger(sinkLogger, asyncConfig)
+		}
+
+		sinks = append(sinks, logging.Sink{
+			Name:   sinkCfg.Name,
+			Logger: sink,
+			Filter: filter,
+		})
+	}
+
+	return logging.NewMultiLogger(sinks...), closers, nil
+}
+
+func newAsyncConfig(cfg config.SinkConfig) (logging.AsyncConfig, error) {
+
+	asyncConfig := logging.AsyncConfig{
+		BlockTimeout: cfg.BlockTimeout,
+	}
+
+	switch cfg.Overflow {
+	case "", "drop_oldest":
+		asyncConfig.Overflow = logging.OverflowDropOldest
+	case "drop_newest":
+		asyncConfig.Overflow = logging.OverflowDropNewest
+	case "block":
+		asyncConfig.Overflow = logging.OverflowBlock
+	default:
+		return asyncConfig, fmt.Errorf("unknown overflow policy %q", cfg.Overflow)
+	}
+
+	return asyncConfig, nil
+}
+
+func closeAll(closers []io.Closer) {
+	for _, closer := range closers {
+		closer.Close()
+	}
+}
+
+// backendLogger is a logger that owns files or connections
+type backendLogger interface {
+	core.Logger
+	io.Closer
+}
 
-	return manager, nil
+func newBackendLogger(cfg config.LoggerConfig, levels *logging.LevelController) (backendLogger, error) {
+
+	loggingConfig := logging.Config{
+		ConsoleEnabled: cfg.ConsoleEnabled,
+		FileEnabled:    cfg.FileEnabled,
+		FilePath:       cfg.FilePath,
+		Level:          cfg.Level,
+		Encoding:       cfg.Encoding,
+		Schema:         logging.Schema(cfg.Schema),
+		Levels:         levels,
+		Rotation: logging.RotationConfig{
+			MaxSize:        int64(cfg.MaxSizeMB) * 1024 * 1024,
+			Interval:       cfg.RotateInterval,
+			MaxAge:         cfg.MaxAge,
+			MaxBackups:     cfg.MaxBackups,
+			Compress:       cfg.Compress,
+			ReopenOnSIGHUP: cfg.ReopenOnSIGHUP,
+		},
+		Syslog: logging.SyslogConfig{
+			Enabled: cfg.SyslogEnabled,
+			Network: cfg.SyslogNetwork,
+			Address: cfg.SyslogAddress,
+			AppName: cfg.SyslogAppName,
+		},
+	}
+
+	switch cfg.Backend {
+	case "", "zap":
+		return logging.NewZapLogger(loggingConfig)
+	case "slog":
+		return logging.NewSlogLoggerFromConfig(loggingConfig)
+	default:
+		return nil, fmt.Errorf("unknown logger backend %q", cfg.Backend)
+	}
+}
+
+func newFilter(cfg config.SinkFilterConfig) (logging.Filter, error) {
+
+	filter := logging.Filter{
+		Codes:     cfg.Codes,
+		MinStatus: cfg.MinStatus,
+		MaxStatus: cfg.MaxStatus,
+		Domains:   cfg.Domains,
+	}
+
+	if cfg.MinLevel != "" {
+
+		level, err := core.ParseLevel(cfg.MinLevel)
+
+		if err != nil {
+			return filter, err
+		}
+
+		filter.MinLevel = level
+	}
+
+	return filter, nil
+}
+
+func newResponseConfig(cfg config.ResponseConfig) core.ResponseConfig {
+
+	naming := core.NamingSnake
+
+	if cfg.Naming == "camel" {
+		naming = core.NamingCamel
+	}
+
+	return core.ResponseConfig{
+		Naming:           naming,
+		Fields:           cfg.Fields,
+		Envelope:         cfg.Envelope,
+		DetailsAllowlist: cfg.DetailsAllowlist,
+		StrictDetails:    cfg.StrictDetails,
+	}
+}
+
+// newFatalPolicy validates cfg; the escalation engine is built by newEscalationEngine
+func newFatalPolicy(cfg config.FatalConfig) (core.FatalPolicy, error) {
+
+	policy := core.FatalPolicy{
+		ShutdownTimeout: cfg.ShutdownTimeout,
+		ExitOnPanic:     cfg.ExitOnPanic,
+	}
+
+	switch cfg.Action {
+	case "", "log":
+		policy.Action = core.FatalLogOnly
+	case "exit":
+		policy.Action = core.FatalExit
+	case "escalate":
+		policy.Action = core.FatalEscalate
+	default:
+		return policy, fmt.Errorf("unknown fatal action %q", cfg.Action)
+	}
+
+	if policy.Action == core.FatalEscalate && cfg.EscalationWebhook == "" {
+		return policy, errors.New("fatal action escalate requires an escalation webhook")
+	}
+
+	return policy, nil
+}
+
+// newEscalationEngine starts the alerting engine escalated errors are sent to.
+// Framework.Close stops it.
+func newEscalationEngine(cfg config.FatalConfig) *alerting.Engine {
+
+	return alerting.NewEngine(alerting.Config{
+		Rules:    []alerting.Rule{alerting.Any("fatal", alerting.MatchLevel(core.LevelFatal))},
+		Notifier: alerting.NewWebhookNotifier(cfg.EscalationWebhook),
+	})
 }
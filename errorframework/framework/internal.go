@@ -6,7 +6,7 @@ import (
 	"github.com/krisalay/error-framework/core"
 )
 
-func Internal(err error) *core.AppError {
+func (f *Framework) Internal(err error) *core.AppError {
 
 	if err == nil {
 		return nil
@@ -22,7 +22,7 @@ func Internal(err error) *core.AppError {
 		Build()
 }
 
-func NotFound(message string) *core.AppError {
+func (f *Framework) NotFound(message string) *core.AppError {
 
 	return core.New().
 		WithMessage(message).
@@ -33,7 +33,7 @@ func NotFound(message string) *core.AppError {
 		Build()
 }
 
-func AlreadyExists(message string) *core.AppError {
+func (f *Framework) AlreadyExists(message string) *core.AppError {
 
 	return core.New().
 		WithMessage(message).
@@ -43,3 +43,15 @@ func AlreadyExists(message string) *core.AppError {
 		WithLevel(core.LevelWarn).
 		Build()
 }
+
+func Internal(err error) *core.AppError {
+	return Default().Internal(err)
+}
+
+func NotFound(message string) *core.AppError {
+	return Default().NotFound(message)
+}
+
+func AlreadyExists(message string) *core.AppError {
+	return Default().AlreadyExists(message)
+}
@@ -1,47 +1,147 @@
 package framework
 
-// import (
-// 	"context"
+import (
+	"context"
+	"errors"
+	"sync"
 
-// 	"github.com/krisalay/error-framework/core"
-// )
+	"github.com/krisalay/error-framework/core"
+	"github.com/krisalay/error-framework/utils"
+)
 
-// func Recover(ctx context.Context) *core.AppError {
+// Recover converts a panic into an AppError stored in errp.
+// It must be deferred directly: defer framework.Recover(ctx, &err)
+func Recover(ctx context.Context, errp *error) {
 
-// 	recovered := recover()
+	if recovered := recover(); recovered != nil {
+		FromContext(ctx).recovered(ctx, recovered, errp)
+	}
+}
 
-// 	if recovered == nil {
-// 		return nil
-// 	}
+// Recover converts a panic into an AppError stored in errp.
+// It must be deferred directly: defer f.Recover(ctx, &err)
+func (f *Framework) Recover(ctx context.Context, errp *error) {
 
-// 	appErr := core.New().
-// 		WithMessage("Internal server error").
-// 		WithCode(core.CodeInternalError).
-// 		WithLevel(core.LevelFatal).
-// 		WithSensitive(true).
-// 		WithDetail("panic", recovered).
-// 		Build()
+	if recovered := recover(); recovered != nil {
+		f.recovered(ctx, recovered, errp)
+	}
+}
 
-// 	// SAFE access to instance (DO NOT call get())
-// 	if instance != nil && instance.manager != nil {
-// 		instance.manager.Handle(ctx, appErr)
-// 	}
+// Go runs fn in a goroutine that cannot crash the process.
+// Panics and returned errors are handled by the manager.
+// fn keeps the values of ctx, such as the trace ID, but not its cancellation.
+func Go(ctx context.Context, fn func(ctx context.Context) error) {
+	FromContext(ctx).Go(ctx, fn)
+}
 
-// 	return appErr
-// }
+func (f *Framework) Go(ctx context.Context, fn func(ctx context.Context) error) {
 
-// func RecoverAndWrap(ctx context.Context, message string) *core.AppError {
+	ctx = context.WithoutCancel(ctx)
 
-// 	recovered := recover()
+	go func() {
 
-// 	if recovered == nil {
-// 		return nil
-// 	}
+		var err error
 
-// 	return Wrap(
-// 		core.New().
-// 			WithDetail("panic", recovered).
-// 			Build(),
-// 		message,
-// 	)
-// }
+		defer func() {
+			if err != nil && f.manager != nil {
+				f.manager.Handle(ctx, err)
+			}
+		}()
+
+		defer f.Recover(ctx, &err)
+
+		err = fn(ctx)
+	}()
+}
+
+func (f *Framework) recovered(ctx context.Context, recovered any, errp *error) {
+
+	// Captured while still on the panicking stack, so it includes the panic site
+	stack := utils.CapturePanic()
+
+	var appErr *core.AppError
+
+	if f.manager != nil {
+		appErr = f.manager.HandlePanicWithStack(ctx, recovered, stack)
+	} else {
+		appErr = core.NewPanicError(recovered, stack)
+	}
+
+	if errp != nil {
+		*errp = appErr
+	}
+}
+
+// Group runs goroutines like errgroup.Group.
+// A panic or a LevelFatal error cancels the group's context.
+type Group struct {
+	f      *Framework
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	wg      sync.WaitGroup
+	errOnce sync.Once
+	err     error
+}
+
+// NewGroup returns a group and the context shared by its goroutines
+func NewGroup(ctx context.Context) (*Group, context.Context) {
+	return FromContext(ctx).NewGroup(ctx)
+}
+
+func (f *Framework) NewGroup(ctx context.Context) (*Group, context.Context) {
+
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	return &Group{
+		f:      f,
+		ctx:    ctx,
+		cancel: cancel,
+	}, ctx
+}
+
+func (g *Group) Go(fn func(ctx context.Context) error) {
+
+	g.wg.Add(1)
+
+	go func() {
+
+		defer g.wg.Done()
+
+		var err error
+		returned := false
+
+		defer func() {
+
+			if err == nil {
+				return
+			}
+
+			g.errOnce.Do(func() {
+				g.err = err
+			})
+
+			// Only reached without returning when fn panicked
+			var appErr *core.AppError
+			fatal := errors.As(err, &appErr) && appErr.Level == core.LevelFatal
+
+			if !returned || fatal {
+				g.cancel(err)
+			}
+		}()
+
+		defer g.f.Recover(g.ctx, &err)
+
+		err = fn(g.ctx)
+		returned = true
+	}()
+}
+
+// Wait blocks until all goroutines return and reports the first error
+func (g *Group) Wait() error {
+
+	g.wg.Wait()
+	g.cancel(nil)
+
+	return g.err
+}
@@ -0,0 +1,129 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krisalay/error-framework/core"
+	"github.com/krisalay/error-framework/errtest"
+)
+
+type staticTraceProvider struct{}
+
+func (s *staticTraceProvider) GetTraceID(ctx context.Context) string {
+
+	if traceID, ok := ctx.Value("trace").(string); ok {
+		return traceID
+	}
+
+	return "generated"
+}
+
+func panickingFunction() {
+	panic("boom")
+}
+
+func TestRecover(t *testing.T) {
+
+	run := func() (err error) {
+		defer Recover(context.Background(), &err)
+		panickingFunction()
+		return nil
+	}
+
+	err := run()
+
+	var appErr *core.AppError
+
+	if !errors.As(err, &appErr) {
+		t.Fatal("Panic not converted to AppError")
+	}
+
+	if !strings.Contains(appErr.StackTrace, "panickingFunction") {
+		t.Fatalf("Stack does not include panic site:\n%s", appErr.StackTrace)
+	}
+}
+
+func TestGo_HandlesPanicWithParentTraceID(t *testing.T) {
+
+	logger := errtest.NewRecorder()
+
+	f := New(core.NewManager(core.ManagerConfig{
+		Logger:        logger,
+		TraceProvider: &staticTraceProvider{},
+	}), nil, nil)
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), "trace", "parent"))
+	cancel()
+
+	f.Go(ctx, func(ctx context.Context) error {
+
+		if ctx.Err() != nil {
+			t.Error("Parent cancellation leaked into background goroutine")
+		}
+
+		panic("background failure")
+	})
+
+	if !logger.Wait(1, time.Second) {
+		t.Fatal("Panic not logged")
+	}
+
+	if logger.Errors()[0].TraceID != "parent" {
+		t.Fatal("Trace ID not propagated from parent")
+	}
+}
+
+func TestGroup_PanicCancelsSiblings(t *testing.T) {
+
+	f := New(core.NewManager(core.ManagerConfig{
+		Logger: errtest.NewRecorder(),
+	}), nil, nil)
+
+	group, ctx := f.NewGroup(context.Background())
+
+	group.Go(func(ctx context.Context) error {
+		panic("boom")
+	})
+
+	group.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	err := group.Wait()
+
+	var appErr *core.AppError
+
+	if !errors.As(err, &appErr) && !errors.Is(err, context.Canceled) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if ctx.Err() == nil {
+		t.Fatal("Group context not canceled")
+	}
+}
+
+func TestGroup_OrdinaryErrorKeepsSiblings(t *testing.T) {
+
+	group, ctx := New(nil, nil, nil).NewGroup(context.Background())
+
+	group.Go(func(ctx context.Context) error {
+		return NotFound("missing")
+	})
+
+	group.Go(func(ctx context.Context) error {
+		return nil
+	})
+
+	if err := group.Wait(); err == nil || err.Error() != "missing" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if context.Cause(ctx) != context.Canceled {
+		t.Fatal("Group canceled by a non-fatal error")
+	}
+}
@@ -2,13 +2,19 @@ package framework
 
 import "github.com/krisalay/error-framework/core"
 
-func Validation(err error) *core.AppError {
+func (f *Framework) Validation(err error) *core.AppError {
 
 	if err == nil {
 		return nil
 	}
 
-	f := get()
+	if f.validatorAdapter == nil {
+		return f.Internal(err)
+	}
 
 	return f.validatorAdapter.FromValidationError(err)
 }
+
+func Validation(err error) *core.AppError {
+	return Default().Validation(err)
+}
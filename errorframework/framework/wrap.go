@@ -7,7 +7,7 @@ import (
 )
 
 // Wrap adds context to an existing error
-func Wrap(err error, message string) *core.AppError {
+func (f *Framework) Wrap(err error, message string) *core.AppError {
 
 	if err == nil {
 		return nil
@@ -38,7 +38,7 @@ func Wrap(err error, message string) *core.AppError {
 		Build()
 }
 
-func WrapWithCode(err error, code string, message string) *core.AppError {
+func (f *Framework) WrapWithCode(err error, code string, message string) *core.AppError {
 
 	return core.New().
 		WithMessage(message).
@@ -47,3 +47,11 @@ func WrapWithCode(err error, code string, message string) *core.AppError {
 		WithSensitive(true).
 		Build()
 }
+
+func Wrap(err error, message string) *core.AppError {
+	return Default().Wrap(err, message)
+}
+
+func WrapWithCode(err error, code string, message string) *core.AppError {
+	return Default().WrapWithCode(err, code, message)
+}
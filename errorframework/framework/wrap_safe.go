@@ -7,7 +7,7 @@ import (
 )
 
 // WrapSafe wraps an error with a safe message that can be exposed to clients.
-func WrapSafe(err error, message string) *core.AppError {
+func (f *Framework) WrapSafe(err error, message string) *core.AppError {
 
 	if err == nil {
 		return nil
@@ -37,3 +37,7 @@ func WrapSafe(err error, message string) *core.AppError {
 		WithInternal(err).
 		Build()
 }
+
+func WrapSafe(err error, message string) *core.AppError {
+	return Default().WrapSafe(err, message)
+}
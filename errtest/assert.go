@@ -0,0 +1,88 @@
+package errtest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/krisalay/error-framework/core"
+)
+
+// AppError returns the AppError in err's chain, failing the test if there is none
+func AppError(t testing.TB, err error) *core.AppError {
+
+	t.Helper()
+
+	var appErr *core.AppError
+
+	if !errors.As(err, &appErr) {
+		t.Fatalf("expected an *core.AppError, got %T: %v", err, err)
+	}
+
+	return appErr
+}
+
+// AssertCode fails the test unless err is an AppError with code
+func AssertCode(t testing.TB, err error, code string) *core.AppError {
+
+	t.Helper()
+
+	appErr := AppError(t, err)
+
+	if appErr.Code != code {
+		t.Errorf("expected code %s, got %s", code, appErr.Code)
+	}
+
+	return appErr
+}
+
+// AssertStatus fails the test unless err is an AppError with status
+func AssertStatus(t testing.TB, err error, status int) *core.AppError {
+
+	t.Helper()
+
+	appErr := AppError(t, err)
+
+	if appErr.Status != status {
+		t.Errorf("expected status %d, got %d", status, appErr.Status)
+	}
+
+	return appErr
+}
+
+// AssertNotSensitive fails the test if err's message would be hidden from clients
+func AssertNotSensitive(t testing.TB, err error) *core.AppError {
+
+	t.Helper()
+
+	appErr := AppError(t, err)
+
+	if appErr.IsSensitive {
+		t.Errorf("expected %s to be safe for clients, but it is sensitive", appErr.Code)
+	}
+
+	return appErr
+}
+
+// AssertLogged fails the test unless rec logged an error with code
+func AssertLogged(t testing.TB, rec *Recorder, code string) *core.AppError {
+
+	t.Helper()
+
+	err, ok := rec.Find(code)
+
+	if !ok {
+		t.Fatalf("expected %s to be logged, logged codes: %v", code, rec.Codes())
+	}
+
+	return err
+}
+
+// AssertNotLogged fails the test if rec logged an error with code
+func AssertNotLogged(t testing.TB, rec *Recorder, code string) {
+
+	t.Helper()
+
+	if _, ok := rec.Find(code); ok {
+		t.Errorf("expected %s not to be logged", code)
+	}
+}
@@ -0,0 +1,95 @@
+package errtest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/krisalay/error-framework/core"
+	"github.com/labstack/echo/v4"
+)
+
+type fakeT struct {
+	testing.TB
+	failed bool
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.failed = true
+}
+
+func TestAssertions(t *testing.T) {
+
+	err := fmt.Errorf("lookup: %w", core.New().
+		WithMessage("Order not found").
+		WithCode(core.CodeNotFound).
+		WithStatus(http.StatusNotFound).
+		WithSensitive(false).
+		Build())
+
+	AssertCode(t, err, core.CodeNotFound)
+	AssertStatus(t, err, http.StatusNotFound)
+	AssertNotSensitive(t, err)
+
+	fake := &fakeT{TB: t}
+
+	AssertCode(fake, err, core.CodeTimeout)
+
+	if !fake.failed {
+		t.Fatal("Mismatched code not reported")
+	}
+}
+
+func TestRecorder_Wait(t *testing.T) {
+
+	rec := NewRecorder()
+
+	go rec.Log(core.New().WithCode(core.CodeTimeout).Build())
+
+	if !rec.Wait(1, time.Second) {
+		t.Fatal("Wait timed out")
+	}
+
+	AssertLogged(t, rec, core.CodeTimeout)
+	AssertNotLogged(t, rec, core.CodeNotFound)
+
+	if rec.Wait(2, 10*time.Millisecond) {
+		t.Fatal("Wait returned before enough errors were logged")
+	}
+}
+
+func TestAssertErrorResponse(t *testing.T) {
+
+	rec := NewRecorder()
+
+	e := NewEcho(core.NewManager(core.ManagerConfig{
+		Logger:   rec,
+		Response: core.ResponseConfig{Envelope: "error"},
+	}))
+
+	e.GET("/orders/:id", func(c echo.Context) error {
+		return core.New().
+			WithMessage("Order not found").
+			WithCode(core.CodeNotFound).
+			WithStatus(http.StatusNotFound).
+			WithSensitive(false).
+			WithDetail("order_id", c.Param("id")).
+			Build()
+	})
+
+	response := AssertErrorResponse(t, Serve(e, httptest.NewRequest(http.MethodGet, "/orders/o-1", nil)), http.StatusNotFound, core.CodeNotFound)
+
+	if response.Field(core.FieldMessage) != "Order not found" {
+		t.Fatal("Unexpected message", response.Body)
+	}
+
+	if response.Details()["order_id"] != "o-1" {
+		t.Fatal("Unexpected details", response.Body)
+	}
+
+	AssertLogged(t, rec, core.CodeNotFound)
+}
@@ -0,0 +1,78 @@
+package errtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	echoadapter "github.com/krisalay/error-framework/adapters/echo"
+	"github.com/krisalay/error-framework/core"
+	"github.com/labstack/echo/v4"
+)
+
+// NewEcho returns an Echo instance that renders errors through manager
+func NewEcho(manager *core.Manager) *echo.Echo {
+
+	e := echo.New()
+	e.HTTPErrorHandler = echoadapter.NewHandler(manager).Handle
+
+	return e
+}
+
+// Serve runs req through handler and returns the recorded response
+func Serve(handler http.Handler, req *http.Request) *httptest.ResponseRecorder {
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec
+}
+
+// ErrorResponse is a decoded JSON error response
+type ErrorResponse struct {
+	Status int
+	Header http.Header
+	Body   map[string]any // the error object, unwrapped from a response envelope
+}
+
+// Field returns a field of the error object, e.g. "trace_id"
+func (r ErrorResponse) Field(name string) any {
+	return r.Body[name]
+}
+
+// Details returns the details object of the response
+func (r ErrorResponse) Details() map[string]any {
+
+	details, _ := r.Body[core.FieldDetails].(map[string]any)
+
+	return details
+}
+
+// AssertErrorResponse decodes rec's JSON body and fails the test unless it carries status and code
+func AssertErrorResponse(t testing.TB, rec *httptest.ResponseRecorder, status int, code string) ErrorResponse {
+
+	t.Helper()
+
+	var body map[string]any
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("response is not a JSON object: %v\n%s", err, rec.Body.String())
+	}
+
+	response := ErrorResponse{
+		Status: rec.Code,
+		Header: rec.Header(),
+		Body:   core.UnwrapEnvelope(body),
+	}
+
+	if rec.Code != status {
+		t.Errorf("expected HTTP status %d, got %d", status, rec.Code)
+	}
+
+	if got := response.Field(core.FieldCode); got != code {
+		t.Errorf("expected code %s, got %v\n%s", code, got, rec.Body.String())
+	}
+
+	return response
+}
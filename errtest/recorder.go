@@ -0,0 +1,121 @@
+// Package errtest provides a recording logger and assertions for testing error contracts.
+package errtest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/krisalay/error-framework/core"
+)
+
+// Recorder is a concurrency-safe core.Logger that keeps every logged error.
+// The zero value is ready to use.
+type Recorder struct {
+	mu      sync.Mutex
+	errs    []*core.AppError
+	changed chan struct{}
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+func (r *Recorder) Log(err *core.AppError) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errs = append(r.errs, err)
+
+	if r.changed != nil {
+		close(r.changed)
+		r.changed = nil
+	}
+}
+
+// Errors returns the logged errors in order
+func (r *Recorder) Errors() []*core.AppError {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*core.AppError(nil), r.errs...)
+}
+
+// Codes returns the codes of the logged errors in order
+func (r *Recorder) Codes() []string {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	codes := make([]string, len(r.errs))
+
+	for i, err := range r.errs {
+		codes[i] = err.Code
+	}
+
+	return codes
+}
+
+func (r *Recorder) Len() int {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.errs)
+}
+
+// Find returns the first logged error with code
+func (r *Recorder) Find(code string) (*core.AppError, bool) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, err := range r.errs {
+		if err.Code == code {
+			return err, true
+		}
+	}
+
+	return nil, false
+}
+
+func (r *Recorder) Reset() {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errs = nil
+}
+
+// Wait blocks until at least n errors are logged, reporting false after timeout.
+// Use it for errors logged from other goroutines.
+func (r *Recorder) Wait(n int, timeout time.Duration) bool {
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+
+		r.mu.Lock()
+
+		if len(r.errs) >= n {
+			r.mu.Unlock()
+			return true
+		}
+
+		if r.changed == nil {
+			r.changed = make(chan struct{})
+		}
+
+		changed := r.changed
+
+		r.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-deadline.C:
+			return false
+		}
+	}
+}
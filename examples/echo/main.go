@@ -4,7 +4,6 @@ import (
 	echoadapter "github.com/krisalay/error-framework/adapters/echo"
 	"github.com/krisalay/error-framework/errorframework/config"
 	"github.com/krisalay/error-framework/errorframework/framework"
-	"github.com/krisalay/error-framework/utils"
 	"github.com/labstack/echo/v4"
 )
 
@@ -39,7 +38,8 @@ func main() {
 		panic(err)
 	}
 
-	traceProvider := utils.NewTraceProvider()
+	// Uses the configured trace ID generator
+	traceProvider := framework.Default().TraceProvider()
 
 	// Echo setup
 	e := echo.New()
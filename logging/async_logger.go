@@ -0,0 +1,308 @@
+package logging
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/krisalay/error-framework/core"
+)
+
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits for room in the queue
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest discards the error being logged
+	OverflowDropNewest
+	// OverflowDropOldest discards the oldest queued error to make room
+	OverflowDropOldest
+)
+
+type AsyncConfig struct {
+	QueueSize int // defaults to 1024
+	BatchSize int // maximum errors handed to the wrapped logger at once, defaults to 64
+	Overflow  OverflowPolicy
+
+	// BlockTimeout bounds the wait of OverflowBlock, after which the error is dropped.
+	// Zero waits indefinitely.
+	BlockTimeout time.Duration
+}
+
+// BatchLogger is optionally implemented by loggers that can write several errors at once
+type BatchLogger interface {
+	LogBatch(errs []*core.AppError)
+}
+
+// AsyncStats reports counters of an AsyncLogger
+type AsyncStats struct {
+	Enqueued      uint64
+	Written       uint64
+	DroppedNewest uint64
+	DroppedOldest uint64
+}
+
+// AsyncLogger moves logging off the request path.
+// Errors are queued and written to the wrapped logger by a background goroutine.
+type AsyncLogger struct {
+	next   core.Logger
+	config AsyncConfig
+	queue  chan *core.AppError
+
+	// mu guards closing the queue; Log never holds it while blocked
+	mu      sync.RWMutex
+	closed  bool
+	closing chan struct{}
+	once    sync.Once
+
+	// accepted counts errors entering the queue, settled those that left it
+	// by being written or dropped. Flush waits for settled to reach accepted.
+	progressMu sync.Mutex
+	accepted   uint64
+	settled    uint64
+	progress   chan struct{}
+
+	enqueued      atomic.Uint64
+	written       atomic.Uint64
+	droppedNewest atomic.Uint64
+	droppedOldest atomic.Uint64
+
+	done chan struct{}
+}
+
+func NewAsyncLogger(next core.Logger, config AsyncConfig) *AsyncLogger {
+
+	if next == nil {
+		panic("Logger is required for async logger")
+	}
+
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1024
+	}
+
+	if config.BatchSize <= 0 {
+		config.BatchSize = 64
+	}
+
+	a := &AsyncLogger{
+		next:     next,
+		config:   config,
+		queue:    make(chan *core.AppError, config.QueueSize),
+		closing:  make(chan struct{}),
+		progress: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	go a.run()
+
+	return a
+}
+
+func (a *AsyncLogger) Log(err *core.AppError) {
+
+	if err == nil {
+		return
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	// After shutdown nothing drains the queue, write synchronously instead
+	if a.closed {
+		a.writeSync(err)
+		return
+	}
+
+	a.accept()
+
+	switch a.config.Overflow {
+
+	case OverflowDropNewest:
+
+		select {
+		case a.queue <- err:
+			a.enqueued.Add(1)
+		default:
+			a.droppedNewest.Add(1)
+			a.settle(1)
+		}
+
+	case OverflowDropOldest:
+
+		for {
+			select {
+			case a.queue <- err:
+				a.enqueued.Add(1)
+				return
+			default:
+			}
+
+			select {
+			case <-a.queue:
+				a.droppedOldest.Add(1)
+				a.settle(1)
+			default:
+			}
+		}
+
+	default:
+
+		var timeout <-chan time.Time
+
+		if a.config.BlockTimeout > 0 {
+			timer := time.NewTimer(a.config.BlockTimeout)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+
+		// Shutdown closes closing before taking the lock, so a blocked send cannot stall it
+		select {
+		case a.queue <- err:
+			a.enqueued.Add(1)
+		case <-timeout:
+			a.droppedNewest.Add(1)
+			a.settle(1)
+		case <-a.closing:
+			a.settle(1)
+			a.writeSync(err)
+		}
+	}
+}
+
+// Flush waits until every error logged before the call has been written or dropped.
+// Errors logged while it waits are not waited for.
+func (a *AsyncLogger) Flush(ctx context.Context) error {
+
+	a.progressMu.Lock()
+	watermark := a.accepted
+	a.progressMu.Unlock()
+
+	for {
+		a.progressMu.Lock()
+		reached := a.settled >= watermark
+		progress := a.progress
+		a.progressMu.Unlock()
+
+		if reached {
+			return nil
+		}
+
+		select {
+		case <-progress:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Shutdown stops accepting queued errors and waits for the queue to drain.
+// Errors logged afterwards are written synchronously.
+func (a *AsyncLogger) Shutdown(ctx context.Context) error {
+
+	a.once.Do(func() {
+
+		// Wake senders blocked on a full queue before waiting for them
+		close(a.closing)
+
+		// Senders may still be writing synchronously; don't let them hold up ctx
+		go func() {
+			a.mu.Lock()
+			a.closed = true
+			close(a.queue)
+			a.mu.Unlock()
+		}()
+	})
+
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WriteErrors returns the write errors of the wrapped logger, if it reports them
+func (a *AsyncLogger) WriteErrors() uint64 {
+
+	if reporter, ok := a.next.(WriteErrorReporter); ok {
+		return reporter.WriteErrors()
+	}
+
+	return 0
+}
+
+func (a *AsyncLogger) Stats() AsyncStats {
+	return AsyncStats{
+		Enqueued:      a.enqueued.Load(),
+		Written:       a.written.Load(),
+		DroppedNewest: a.droppedNewest.Load(),
+		DroppedOldest: a.droppedOldest.Load(),
+	}
+}
+
+func (a *AsyncLogger) run() {
+
+	defer close(a.done)
+
+	batch := make([]*core.AppError, 0, a.config.BatchSize)
+
+	for err := range a.queue {
+
+		batch = append(batch[:0], err)
+
+	drain:
+		for len(batch) < a.config.BatchSize {
+			select {
+			case next, ok := <-a.queue:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, next)
+			default:
+				break drain
+			}
+		}
+
+		a.write(batch)
+	}
+}
+
+func (a *AsyncLogger) write(batch []*core.AppError) {
+
+	if batchLogger, ok := a.next.(BatchLogger); ok {
+		batchLogger.LogBatch(batch)
+	} else {
+		for _, err := range batch {
+			a.next.Log(err)
+		}
+	}
+
+	a.written.Add(uint64(len(batch)))
+	a.settle(len(batch))
+}
+
+func (a *AsyncLogger) writeSync(err *core.AppError) {
+	a.next.Log(err)
+	a.written.Add(1)
+}
+
+func (a *AsyncLogger) accept() {
+
+	a.progressMu.Lock()
+	defer a.progressMu.Unlock()
+
+	a.accepted++
+}
+
+// settle records errors leaving the queue and wakes waiting Flush calls
+func (a *AsyncLogger) settle(n int) {
+
+	a.progressMu.Lock()
+	defer a.progressMu.Unlock()
+
+	a.settled += uint64(n)
+
+	close(a.progress)
+	a.progress = make(chan struct{})
+}
@@ -0,0 +1,226 @@
+package logging
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/krisalay/error-framework/core"
+	"github.com/krisalay/error-framework/errtest"
+)
+
+// gatedLogger holds every Log until gate is closed, to keep the async worker busy
+type gatedLogger struct {
+	errtest.Recorder
+	gate chan struct{}
+}
+
+func newGatedLogger() *gatedLogger {
+	return &gatedLogger{gate: make(chan struct{})}
+}
+
+func (g *gatedLogger) Log(err *core.AppError) {
+
+	<-g.gate
+
+	g.Recorder.Log(err)
+}
+
+func TestAsyncLogger_FlushWritesEverything(t *testing.T) {
+
+	next := errtest.NewRecorder()
+
+	logger := NewAsyncLogger(next, AsyncConfig{QueueSize: 4})
+
+	for i := 0; i < 20; i++ {
+		logger.Log(core.New().WithCode("CODE").Build())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := logger.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(next.Codes()) != 20 {
+		t.Fatalf("Expected 20 entries, got %d", len(next.Codes()))
+	}
+}
+
+func TestAsyncLogger_DropOldest(t *testing.T) {
+
+	next := newGatedLogger()
+
+	logger := NewAsyncLogger(next, AsyncConfig{QueueSize: 1, BatchSize: 1, Overflow: OverflowDropOldest})
+
+	// The worker takes the first error and blocks on the gate
+	logger.Log(core.New().WithCode("FIRST").Build())
+
+	for logger.Stats().Enqueued == 0 || len(logger.queue) != 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	logger.Log(core.New().WithCode("SECOND").Build())
+	logger.Log(core.New().WithCode("THIRD").Build())
+
+	close(next.gate)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := logger.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	logged := next.Codes()
+
+	if len(logged) != 2 || logged[0] != "FIRST" || logged[1] != "THIRD" {
+		t.Fatalf("Unexpected entries: %v", logged)
+	}
+
+	if logger.Stats().DroppedOldest != 1 {
+		t.Fatal("Dropped oldest not counted")
+	}
+}
+
+func TestAsyncLogger_DropNewest(t *testing.T) {
+
+	next := newGatedLogger()
+
+	logger := NewAsyncLogger(next, AsyncConfig{QueueSize: 1, BatchSize: 1, Overflow: OverflowDropNewest})
+
+	logger.Log(core.New().WithCode("FIRST").Build())
+
+	for logger.Stats().Enqueued == 0 || len(logger.queue) != 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	logger.Log(core.New().WithCode("SECOND").Build())
+	logger.Log(core.New().WithCode("THIRD").Build())
+
+	close(next.gate)
+
+	if err := logger.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	logged := next.Codes()
+
+	if len(logged) != 2 || logged[1] != "SECOND" {
+		t.Fatalf("Unexpected entries: %v", logged)
+	}
+
+	if logger.Stats().DroppedNewest != 1 {
+		t.Fatal("Dropped newest not counted")
+	}
+}
+
+func TestAsyncLogger_LogAfterShutdown(t *testing.T) {
+
+	next := errtest.NewRecorder()
+
+	logger := NewAsyncLogger(next, AsyncConfig{})
+
+	if err := logger.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	logger.Log(core.New().WithCode("LATE").Build())
+
+	if len(next.Codes()) != 1 {
+		t.Fatal("Error logged after shutdown was lost")
+	}
+}
+
+func TestAsyncLogger_ShutdownWithBlockedSender(t *testing.T) {
+
+	next := newGatedLogger()
+
+	logger := NewAsyncLogger(next, AsyncConfig{QueueSize: 1, BatchSize: 1})
+
+	// The worker blocks on the first error and the second fills the queue
+	logger.Log(core.New().WithCode("FIRST").Build())
+	logger.Log(core.New().WithCode("SECOND").Build())
+
+	blocked := make(chan struct{})
+
+	go func() {
+		logger.Log(core.New().WithCode("THIRD").Build())
+		close(blocked)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// The worker is still stuck, so the deadline must be honored
+	if err := logger.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Fatal("Expected deadline exceeded, got", err)
+	}
+
+	close(next.gate)
+
+	select {
+	case <-blocked:
+	case <-time.After(time.Second):
+		t.Fatal("Blocked sender not released by shutdown")
+	}
+
+	if err := logger.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(next.Codes()) != 3 {
+		t.Fatalf("Unexpected entries: %v", next.Codes())
+	}
+}
+
+func TestAsyncLogger_FlushUnderSteadyLoad(t *testing.T) {
+
+	logger := NewAsyncLogger(errtest.NewRecorder(), AsyncConfig{QueueSize: 8})
+	defer logger.Shutdown(context.Background())
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				logger.Log(core.New().Build())
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := logger.Flush(ctx); err != nil {
+		t.Fatal("Flush did not return under load:", err)
+	}
+}
+
+func TestAsyncLogger_BlockTimeout(t *testing.T) {
+
+	next := newGatedLogger()
+
+	logger := NewAsyncLogger(next, AsyncConfig{QueueSize: 1, BatchSize: 1, BlockTimeout: 10 * time.Millisecond})
+
+	logger.Log(core.New().WithCode("FIRST").Build())
+
+	for logger.Stats().Enqueued == 0 || len(logger.queue) != 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	logger.Log(core.New().WithCode("SECOND").Build())
+	logger.Log(core.New().WithCode("THIRD").Build())
+
+	if logger.Stats().DroppedNewest != 1 {
+		t.Fatal("Timed out error not dropped")
+	}
+
+	close(next.gate)
+	logger.Shutdown(context.Background())
+}
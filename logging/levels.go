@@ -0,0 +1,338 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/krisalay/error-framework/core"
+)
+
+type levelOverride struct {
+	level   core.ErrorLevel
+	expires time.Time // zero never expires
+}
+
+func (o levelOverride) active(now time.Time) bool {
+	return o.expires.IsZero() || now.Before(o.expires)
+}
+
+// LevelController holds the minimum level a logger writes, adjustable at runtime.
+// Overrides per code and per domain take precedence and may revert after a TTL.
+type LevelController struct {
+	mu      sync.RWMutex
+	base    core.ErrorLevel
+	global  *levelOverride
+	codes   map[string]levelOverride
+	domains map[string]levelOverride
+	now     func() time.Time
+}
+
+func NewLevelController(level core.ErrorLevel) *LevelController {
+	return &LevelController{
+		base:    level,
+		codes:   make(map[string]levelOverride),
+		domains: make(map[string]levelOverride),
+		now:     time.Now,
+	}
+}
+
+// Level returns the global minimum level
+func (c *LevelController) Level() core.ErrorLevel {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.globalLevel(c.now())
+}
+
+// SetLevel changes the global level, reverting after ttl when ttl is positive
+func (c *LevelController) SetLevel(level core.ErrorLevel, ttl time.Duration) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ttl <= 0 {
+		c.base = level
+		c.global = nil
+		return
+	}
+
+	c.global = &levelOverride{level: level, expires: c.now().Add(ttl)}
+	c.prune(c.now())
+}
+
+// SetCodeLevel sets the minimum level for errors with code, reverting after ttl when positive
+func (c *LevelController) SetCodeLevel(code string, level core.ErrorLevel, ttl time.Duration) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.codes[code] = c.override(level, ttl)
+	c.prune(c.now())
+}
+
+// SetDomainLevel sets the minimum level for errors in domain, reverting after ttl when positive
+func (c *LevelController) SetDomainLevel(domain string, level core.ErrorLevel, ttl time.Duration) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.domains[domain] = c.override(level, ttl)
+	c.prune(c.now())
+}
+
+// ClearOverrides removes all code and domain overrides
+func (c *LevelController) ClearOverrides() {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.codes = make(map[string]levelOverride)
+	c.domains = make(map[string]levelOverride)
+}
+
+// Enabled reports whether err should be logged.
+// Expired overrides met on the way are removed.
+func (c *LevelController) Enabled(err *core.AppError) bool {
+
+	now := c.now()
+
+	c.mu.RLock()
+	enabled, expired := c.enabled(err, now)
+	c.mu.RUnlock()
+
+	if expired {
+		c.mu.Lock()
+		c.prune(now)
+		c.mu.Unlock()
+	}
+
+	return enabled
+}
+
+// enabled also reports whether an expired override was seen
+func (c *LevelController) enabled(err *core.AppError, now time.Time) (bool, bool) {
+
+	expired := false
+
+	if override, ok := c.codes[err.Code]; ok {
+
+		if override.active(now) {
+			return err.Level >= override.level, false
+		}
+
+		expired = true
+	}
+
+	domain := err.Domain
+	if domain == "" {
+		domain = core.DomainForCode(err.Code)
+	}
+
+	if override, ok := c.domains[domain]; ok {
+
+		if override.active(now) {
+			return err.Level >= override.level, expired
+		}
+
+		expired = true
+	}
+
+	if c.global != nil && !c.global.active(now) {
+		expired = true
+	}
+
+	return err.Level >= c.globalLevel(now), expired
+}
+
+// prune removes expired overrides, the caller holds the write lock
+func (c *LevelController) prune(now time.Time) {
+
+	if c.global != nil && !c.global.active(now) {
+		c.global = nil
+	}
+
+	for code, override := range c.codes {
+		if !override.active(now) {
+			delete(c.codes, code)
+		}
+	}
+
+	for domain, override := range c.domains {
+		if !override.active(now) {
+			delete(c.domains, domain)
+		}
+	}
+}
+
+func (c *LevelController) override(level core.ErrorLevel, ttl time.Duration) levelOverride {
+
+	override := levelOverride{level: level}
+
+	if ttl > 0 {
+		override.expires = c.now().Add(ttl)
+	}
+
+	return override
+}
+
+func (c *LevelController) globalLevel(now time.Time) core.ErrorLevel {
+
+	if c.global != nil && c.global.active(now) {
+		return c.global.level
+	}
+
+	return c.base
+}
+
+type levelOverrideJSON struct {
+	Level     string     `json:"level"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type levelStateJSON struct {
+	levelOverrideJSON
+	Codes   map[string]levelOverrideJSON `json:"codes"`
+	Domains map[string]levelOverrideJSON `json:"domains"`
+}
+
+type levelUpdateJSON struct {
+	Level  string `json:"level"`
+	Code   string `json:"code,omitempty"`
+	Domain string `json:"domain,omitempty"`
+	TTL    string `json:"ttl,omitempty"` // Go duration, e.g. "10m"
+}
+
+// ServeHTTP exposes the levels as JSON.
+// GET returns the current state; PUT applies a levelUpdateJSON to the global level, a code or a domain.
+func (c *LevelController) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	switch r.Method {
+
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, c.state())
+
+	case http.MethodPut:
+		if err := c.apply(r); err != nil {
+			writeJSON(w, err.Status, map[string]any{
+				"code":    err.Code,
+				"message": err.SafeMessage(),
+				"status":  err.Status,
+			})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, c.state())
+
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (c *LevelController) apply(r *http.Request) *core.AppError {
+
+	var update levelUpdateJSON
+
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		return invalidLevelUpdate("invalid JSON body")
+	}
+
+	level, err := core.ParseLevel(update.Level)
+
+	if err != nil {
+		return invalidLevelUpdate(fmt.Sprintf("unknown level %q", update.Level))
+	}
+
+	var ttl time.Duration
+
+	if update.TTL != "" {
+
+		ttl, err = time.ParseDuration(update.TTL)
+
+		if err != nil || ttl < 0 {
+			return invalidLevelUpdate(fmt.Sprintf("invalid ttl %q", update.TTL))
+		}
+	}
+
+	switch {
+	case update.Code != "" && update.Domain != "":
+		return invalidLevelUpdate("code and domain are mutually exclusive")
+	case update.Code != "":
+		c.SetCodeLevel(update.Code, level, ttl)
+	case update.Domain != "":
+		c.SetDomainLevel(update.Domain, level, ttl)
+	default:
+		c.SetLevel(level, ttl)
+	}
+
+	return nil
+}
+
+func (c *LevelController) state() levelStateJSON {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+
+	c.prune(now)
+
+	state := levelStateJSON{
+		levelOverrideJSON: levelOverrideJSON{Level: c.globalLevel(now).String()},
+		Codes:             make(map[string]levelOverrideJSON),
+		Domains:           make(map[string]levelOverrideJSON),
+	}
+
+	if c.global != nil && c.global.active(now) {
+		state.ExpiresAt = &c.global.expires
+	}
+
+	for code, override := range c.codes {
+		if override.active(now) {
+			state.Codes[code] = toOverrideJSON(override)
+		}
+	}
+
+	for domain, override := range c.domains {
+		if override.active(now) {
+			state.Domains[domain] = toOverrideJSON(override)
+		}
+	}
+
+	return state
+}
+
+func toOverrideJSON(override levelOverride) levelOverrideJSON {
+
+	result := levelOverrideJSON{Level: override.level.String()}
+
+	if !override.expires.IsZero() {
+		expires := override.expires
+		result.ExpiresAt = &expires
+	}
+
+	return result
+}
+
+func invalidLevelUpdate(message string) *core.AppError {
+
+	return core.New().
+		WithMessage(message).
+		WithCode(core.CodeInvalidInput).
+		WithStatus(http.StatusBadRequest).
+		WithLevel(core.LevelWarn).
+		WithSensitive(false).
+		Build()
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	json.NewEncoder(w).Encode(body)
+}
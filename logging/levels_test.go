@@ -0,0 +1,150 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krisalay/error-framework/core"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLevelController_Overrides(t *testing.T) {
+
+	levels := NewLevelController(core.LevelError)
+
+	notFound := core.New().WithCode(core.CodeNotFound).WithLevel(core.LevelInfo).Build()
+
+	if levels.Enabled(notFound) {
+		t.Fatal("Info error enabled at error level")
+	}
+
+	levels.SetCodeLevel(core.CodeNotFound, core.LevelDebug, 0)
+
+	if !levels.Enabled(notFound) {
+		t.Fatal("Code override ignored")
+	}
+
+	levels.ClearOverrides()
+	levels.SetDomainLevel(core.DomainForCode(core.CodeNotFound), core.LevelInfo, 0)
+
+	if !levels.Enabled(notFound) {
+		t.Fatal("Domain override ignored")
+	}
+}
+
+func TestLevelController_TTL(t *testing.T) {
+
+	now := time.Now()
+
+	levels := NewLevelController(core.LevelError)
+	levels.now = func() time.Time { return now }
+
+	levels.SetLevel(core.LevelDebug, time.Minute)
+	levels.SetCodeLevel(core.CodeNotFound, core.LevelFatal, time.Minute)
+
+	if levels.Level() != core.LevelDebug {
+		t.Fatal("Temporary level not applied")
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	if levels.Level() != core.LevelError {
+		t.Fatal("Level not reverted after TTL")
+	}
+
+	if levels.Enabled(core.New().WithCode(core.CodeNotFound).WithLevel(core.LevelWarn).Build()) {
+		t.Fatal("Expired code override should fall back to the global level")
+	}
+
+	if len(levels.codes) != 0 || levels.global != nil {
+		t.Fatal("Expired overrides not removed")
+	}
+}
+
+func TestLevelController_PrunesOnUpdate(t *testing.T) {
+
+	now := time.Now()
+
+	levels := NewLevelController(core.LevelError)
+	levels.now = func() time.Time { return now }
+
+	for _, domain := range []string{"a", "b", "c"} {
+		levels.SetDomainLevel(domain, core.LevelDebug, time.Minute)
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	levels.SetDomainLevel("d", core.LevelDebug, time.Minute)
+
+	if len(levels.domains) != 1 {
+		t.Fatalf("Expected only the live override, got %d", len(levels.domains))
+	}
+}
+
+func TestLevelController_ServeHTTP(t *testing.T) {
+
+	levels := NewLevelController(core.LevelError)
+
+	put := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		levels.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/levels", strings.NewReader(body)))
+		return rec
+	}
+
+	if rec := put(`{"level":"warn"}`); rec.Code != http.StatusOK {
+		t.Fatal("Unexpected status", rec.Code)
+	}
+
+	if levels.Level() != core.LevelWarn {
+		t.Fatal("Global level not updated")
+	}
+
+	if rec := put(`{"code":"NOT_FOUND","level":"debug","ttl":"10m"}`); rec.Code != http.StatusOK {
+		t.Fatal("Unexpected status", rec.Code)
+	}
+
+	rec := put(`{"level":"verbose"}`)
+
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), core.CodeInvalidInput) {
+		t.Fatal("Unknown level not rejected:", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	levels.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/levels", nil))
+
+	body := rec.Body.String()
+
+	if !strings.Contains(body, `"level":"WARN"`) || !strings.Contains(body, `"NOT_FOUND":{"level":"DEBUG"`) {
+		t.Fatal("Unexpected state", body)
+	}
+}
+
+func TestZapLogger_RuntimeLevel(t *testing.T) {
+
+	observed, logs := observer.New(zapcore.DebugLevel)
+
+	levels := NewLevelController(core.LevelError)
+	logger := &ZapLogger{logger: zap.New(observed), levels: levels}
+
+	warning := core.New().WithLevel(core.LevelWarn).Build()
+
+	logger.Log(warning)
+	levels.SetLevel(core.LevelWarn, 0)
+	logger.Log(warning)
+
+	if logs.Len() != 1 {
+		t.Fatal("Expected only the entry after lowering the level, got", logs.Len())
+	}
+}
+
+func TestNewZapLogger_UnknownLevel(t *testing.T) {
+
+	if _, err := NewZapLogger(Config{Level: "verbose"}); err == nil {
+		t.Fatal("Expected an error for an unknown level")
+	}
+}
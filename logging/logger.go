@@ -1,6 +1,6 @@
 package logging
 
-import "go.uber.org/zap/zapcore"
+import "github.com/krisalay/error-framework/core"
 
 type Config struct {
 	ConsoleEnabled bool
@@ -8,21 +8,33 @@ type Config struct {
 	FilePath       string
 	Level          string // debug, info, warn, error, fatal
 	Encoding       string // json or console
+	Schema         Schema // field names written by the zap backend
+	Rotation       RotationConfig
+	Syslog         SyslogConfig     // zap backend only
+	Levels         *LevelController // runtime levels, built from Level when nil
 }
 
-func parseLevel(level string) zapcore.Level {
-	switch level {
-	case "debug":
-		return zapcore.DebugLevel
-	case "info":
-		return zapcore.InfoLevel
-	case "warn":
-		return zapcore.WarnLevel
-	case "error":
-		return zapcore.ErrorLevel
-	case "fatal":
-		return zapcore.FatalLevel
-	default:
-		return zapcore.ErrorLevel
+// parseLevel converts a configured level name, defaulting to error when empty
+func parseLevel(level string) (core.ErrorLevel, error) {
+
+	if level == "" {
+		return core.LevelError, nil
+	}
+
+	return core.ParseLevel(level)
+}
+
+func levelsFor(config Config) (*LevelController, error) {
+
+	if config.Levels != nil {
+		return config.Levels, nil
 	}
+
+	level, err := parseLevel(config.Level)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLevelController(level), nil
 }
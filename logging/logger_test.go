@@ -4,6 +4,9 @@ import (
 	"testing"
 
 	"github.com/krisalay/error-framework/core"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestZapLogger(t *testing.T) {
@@ -19,3 +22,38 @@ func TestZapLogger(t *testing.T) {
 
 	logger.Log(core.New().WithMessage("test").Build())
 }
+
+func TestZapLogger_Attributes(t *testing.T) {
+
+	observed, logs := observer.New(zapcore.DebugLevel)
+
+	logger := &ZapLogger{logger: zap.New(observed)}
+
+	logger.Log(core.New().WithAttribute("user_id", "u-1").Build())
+
+	entries := logs.All()
+
+	if len(entries) != 1 {
+		t.Fatal("Nothing logged")
+	}
+
+	attributes, ok := entries[0].ContextMap()["attributes"].(map[string]any)
+
+	if !ok || attributes["user_id"] != "u-1" {
+		t.Fatal("Attributes not logged")
+	}
+}
+
+func TestZapLogger_FatalDoesNotExit(t *testing.T) {
+
+	logger, err := NewZapLogger(Config{
+		ConsoleEnabled: true,
+		Level:          "debug",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger.Log(core.New().WithLevel(core.LevelFatal).Build())
+}
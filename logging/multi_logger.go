@@ -0,0 +1,148 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/krisalay/error-framework/core"
+)
+
+// Filter selects which errors a sink receives.
+// Zero values match everything.
+type Filter struct {
+	MinLevel  core.ErrorLevel
+	Codes     []string
+	MinStatus int
+	MaxStatus int
+	Domains   []string
+}
+
+func (f Filter) Match(err *core.AppError) bool {
+
+	if err.Level < f.MinLevel {
+		return false
+	}
+
+	if len(f.Codes) > 0 && !slices.Contains(f.Codes, err.Code) {
+		return false
+	}
+
+	if f.MinStatus > 0 && err.Status < f.MinStatus {
+		return false
+	}
+
+	if f.MaxStatus > 0 && err.Status > f.MaxStatus {
+		return false
+	}
+
+	domain := err.Domain
+	if domain == "" {
+		domain = core.DomainForCode(err.Code)
+	}
+
+	if len(f.Domains) > 0 && !slices.Contains(f.Domains, domain) {
+		return false
+	}
+
+	return true
+}
+
+// WriteErrorReporter is implemented by loggers that count entries they failed to write
+type WriteErrorReporter interface {
+	WriteErrors() uint64
+}
+
+type Sink struct {
+	Name   string
+	Logger core.Logger
+	Filter Filter
+}
+
+// MultiLogger fans errors out to several sinks.
+// Sinks are written in order on the caller's goroutine; wrap slow ones, such as
+// network sinks, in an AsyncLogger with a dropping overflow policy.
+// A sink that panics is isolated from the others and counted as a failure.
+type MultiLogger struct {
+	sinks []Sink
+
+	mu       sync.Mutex
+	failures map[string]uint64
+}
+
+func NewMultiLogger(sinks ...Sink) *MultiLogger {
+	return &MultiLogger{
+		sinks:    sinks,
+		failures: make(map[string]uint64),
+	}
+}
+
+func (m *MultiLogger) Log(err *core.AppError) {
+
+	if err == nil {
+		return
+	}
+
+	for _, sink := range m.sinks {
+		if sink.Filter.Match(err) {
+			m.logTo(sink, err)
+		}
+	}
+}
+
+func (m *MultiLogger) logTo(sink Sink, err *core.AppError) {
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			m.mu.Lock()
+			m.failures[sink.Name]++
+			m.mu.Unlock()
+		}
+	}()
+
+	sink.Logger.Log(err)
+}
+
+// Failures returns the number of failed writes per sink name:
+// panics plus the write errors reported by sinks implementing WriteErrorReporter
+func (m *MultiLogger) Failures() map[string]uint64 {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	failures := make(map[string]uint64, len(m.failures))
+
+	for name, count := range m.failures {
+		failures[name] = count
+	}
+
+	for _, sink := range m.sinks {
+		if reporter, ok := sink.Logger.(WriteErrorReporter); ok {
+			if count := reporter.WriteErrors(); count > 0 {
+				failures[sink.Name] += count
+			}
+		}
+	}
+
+	return failures
+}
+
+// Shutdown shuts down every sink that supports it, such as an AsyncLogger
+func (m *MultiLogger) Shutdown(ctx context.Context) error {
+
+	var errs []error
+
+	for _, sink := range m.sinks {
+
+		shutdowner, ok := sink.Logger.(interface {
+			Shutdown(ctx context.Context) error
+		})
+
+		if ok {
+			errs = append(errs, shutdowner.Shutdown(ctx))
+		}
+	}
+
+	return errors.Join(errs...)
+}
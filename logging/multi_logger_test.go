@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/krisalay/error-framework/core"
+	"github.com/krisalay/error-framework/errtest"
+)
+
+type panickingLogger struct{}
+
+func (p *panickingLogger) Log(err *core.AppError) {
+	panic("sink down")
+}
+
+func TestMultiLogger_Filters(t *testing.T) {
+
+	app := errtest.NewRecorder()
+	audit := errtest.NewRecorder()
+	incident := errtest.NewRecorder()
+
+	logger := NewMultiLogger(
+		Sink{Name: "app", Logger: app},
+		Sink{Name: "audit", Logger: audit, Filter: Filter{Domains: []string{core.DomainAuth}}},
+		Sink{Name: "incident", Logger: incident, Filter: Filter{MinLevel: core.LevelFatal}},
+	)
+
+	logger.Log(core.New().WithCode(core.CodeForbidden).WithStatus(403).WithLevel(core.LevelWarn).Build())
+	logger.Log(core.New().WithLevel(core.LevelFatal).Build())
+
+	if len(app.Codes()) != 2 {
+		t.Fatal("Unfiltered sink missed errors")
+	}
+
+	if logged := audit.Codes(); len(logged) != 1 || logged[0] != core.CodeForbidden {
+		t.Fatalf("Audit sink got %v", logged)
+	}
+
+	if logged := incident.Codes(); len(logged) != 1 || logged[0] != core.CodeInternalError {
+		t.Fatalf("Incident sink got %v", logged)
+	}
+}
+
+func TestMultiLogger_IsolatesFailingSink(t *testing.T) {
+
+	healthy := errtest.NewRecorder()
+
+	logger := NewMultiLogger(
+		Sink{Name: "broken", Logger: &panickingLogger{}},
+		Sink{Name: "healthy", Logger: healthy},
+	)
+
+	logger.Log(core.New().Build())
+
+	if len(healthy.Codes()) != 1 {
+		t.Fatal("Healthy sink did not receive error")
+	}
+
+	if logger.Failures()["broken"] != 1 {
+		t.Fatal("Failure not counted")
+	}
+}
+
+func TestFilter_StatusRange(t *testing.T) {
+
+	filter := Filter{MinStatus: 401, MaxStatus: 403}
+
+	if !filter.Match(core.New().WithStatus(403).Build()) {
+		t.Fatal("403 should match")
+	}
+
+	if filter.Match(core.New().WithStatus(500).Build()) {
+		t.Fatal("500 should not match")
+	}
+}
+
+type failingLogger struct{}
+
+func (f *failingLogger) Log(err *core.AppError) {}
+
+func (f *failingLogger) WriteErrors() uint64 {
+	return 3
+}
+
+func TestMultiLogger_CountsWriteErrors(t *testing.T) {
+
+	logger := NewMultiLogger(Sink{Name: "network", Logger: NewAsyncLogger(&failingLogger{}, AsyncConfig{})})
+
+	if logger.Failures()["network"] != 3 {
+		t.Fatal("Write errors not reported", logger.Failures())
+	}
+}
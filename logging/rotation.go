@@ -0,0 +1,341 @@
+package logging
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+type RotationConfig struct {
+	MaxSize    int64         // bytes before rotating, 0 disables size rotation
+	Interval   time.Duration // rotate after the file has been open this long, 0 disables
+	MaxAge     time.Duration // delete rotated files older than this, 0 keeps them
+	MaxBackups int           // rotated files to keep, 0 keeps all
+	Compress   bool          // gzip rotated files
+
+	// ReopenOnSIGHUP reopens the file on SIGHUP, for external tools that move it away
+	ReopenOnSIGHUP bool
+}
+
+const rotationTimeFormat = "20060102T150405.000"
+
+// RotatingFile is a log file that rotates by size and age
+type RotatingFile struct {
+	path   string
+	config RotationConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	signals   chan os.Signal
+	stop      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+
+	// cleanupMu serializes compression and retention of rotated files
+	cleanupMu sync.Mutex
+	cleanups  sync.WaitGroup
+}
+
+func OpenRotatingFile(path string, config RotationConfig) (*RotatingFile, error) {
+
+	r := &RotatingFile{
+		path:   path,
+		config: config,
+		now:    time.Now,
+		stop:   make(chan struct{}),
+	}
+
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+
+	if config.ReopenOnSIGHUP {
+
+		r.signals = make(chan os.Signal, 1)
+		signal.Notify(r.signals, syscall.SIGHUP)
+
+		go r.watchSignals()
+	}
+
+	return r, nil
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.shouldRotate(int64(len(p))) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+
+	return n, err
+}
+
+func (r *RotatingFile) Sync() error {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.file.Sync()
+}
+
+// Rotate moves the current file aside and starts a new one
+func (r *RotatingFile) Rotate() error {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.rotate()
+}
+
+// Reopen opens the file at its path and closes the previous handle.
+// The previous handle is kept when opening fails.
+func (r *RotatingFile) Reopen() error {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.replace()
+}
+
+// Close closes the file. Later calls return the result of the first.
+func (r *RotatingFile) Close() error {
+
+	r.closeOnce.Do(func() {
+
+		if r.signals != nil {
+			signal.Stop(r.signals)
+			close(r.stop)
+		}
+
+		r.mu.Lock()
+		r.closeErr = r.file.Close()
+		r.mu.Unlock()
+
+		r.cleanups.Wait()
+	})
+
+	return r.closeErr
+}
+
+func (r *RotatingFile) watchSignals() {
+
+	for {
+		select {
+		case <-r.signals:
+			r.Reopen()
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+func (r *RotatingFile) open() error {
+
+	file, err := os.OpenFile(
+		r.path,
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY,
+		0644,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	r.file = file
+	r.size = info.Size()
+	r.openedAt = r.now()
+
+	return nil
+}
+
+// replace opens a new handle at the path before closing the current one,
+// so a failed open leaves the file writable
+func (r *RotatingFile) replace() error {
+
+	previous := r.file
+
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	return previous.Close()
+}
+
+func (r *RotatingFile) shouldRotate(incoming int64) bool {
+
+	if r.config.MaxSize > 0 && r.size > 0 && r.size+incoming > r.config.MaxSize {
+		return true
+	}
+
+	if r.config.Interval > 0 && r.now().Sub(r.openedAt) >= r.config.Interval {
+		return true
+	}
+
+	return false
+}
+
+func (r *RotatingFile) rotate() error {
+
+	now := r.now()
+	rotated := r.rotatedName(now)
+
+	// The open handle follows the rename, so nothing is lost if the new file can't be opened
+	if err := os.Rename(r.path, rotated); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := r.replace(); err != nil {
+		return err
+	}
+
+	r.cleanups.Add(1)
+
+	go func() {
+
+		defer r.cleanups.Done()
+
+		r.cleanupMu.Lock()
+		defer r.cleanupMu.Unlock()
+
+		if r.config.Compress {
+			compress(rotated)
+		}
+
+		r.removeExpired(now)
+	}()
+
+	return nil
+}
+
+// rotatedName returns an unused name for a file rotated at now.
+// Rotations within the same millisecond get a sequence suffix, e.g. "app.log.20240102T150405.000-1".
+func (r *RotatingFile) rotatedName(now time.Time) string {
+
+	base := r.path + "." + now.Format(rotationTimeFormat)
+
+	for seq := 0; ; seq++ {
+
+		name := base
+
+		if seq > 0 {
+			name += "-" + strconv.Itoa(seq)
+		}
+
+		if !exists(name) && !exists(name+".gz") {
+			return name
+		}
+	}
+}
+
+func exists(path string) bool {
+
+	_, err := os.Lstat(path)
+
+	return !errors.Is(err, os.ErrNotExist)
+}
+
+func compress(path string) error {
+
+	source, err := os.Open(path)
+
+	if err != nil {
+		return err
+	}
+
+	defer source.Close()
+
+	target, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+
+	if err != nil {
+		return err
+	}
+
+	writer := gzip.NewWriter(target)
+
+	if _, err := io.Copy(writer, source); err != nil {
+		target.Close()
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		target.Close()
+		return err
+	}
+
+	if err := target.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// removeExpired applies MaxBackups and MaxAge to the rotated files
+func (r *RotatingFile) removeExpired(now time.Time) {
+
+	if r.config.MaxBackups <= 0 && r.config.MaxAge <= 0 {
+		return
+	}
+
+	rotated, err := filepath.Glob(r.path + ".*")
+
+	if err != nil {
+		return
+	}
+
+	// Names embed the rotation time, newest first after sorting
+	sort.Sort(sort.Reverse(sort.StringSlice(rotated)))
+
+	cutoff := now.Add(-r.config.MaxAge)
+
+	// Only rotated files count toward MaxBackups, not unrelated siblings such as app.log.bak
+	kept := 0
+
+	for _, path := range rotated {
+
+		stamp := strings.TrimSuffix(strings.TrimPrefix(path, r.path+"."), ".gz")
+		stamp, _, _ = strings.Cut(stamp, "-")
+
+		rotatedAt, err := time.ParseInLocation(rotationTimeFormat, stamp, time.Local)
+
+		if err != nil {
+			continue
+		}
+
+		tooMany := r.config.MaxBackups > 0 && kept >= r.config.MaxBackups
+		tooOld := r.config.MaxAge > 0 && rotatedAt.Before(cutoff)
+
+		if tooMany || tooOld {
+			os.Remove(path)
+			continue
+		}
+
+		kept++
+	}
+}
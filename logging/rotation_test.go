@@ -0,0 +1,236 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingFile_RotatesBySize(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	file, err := OpenRotatingFile(path, RotationConfig{MaxSize: 10, MaxBackups: 2})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock := time.Unix(1000, 0)
+	file.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+
+	for i := 0; i < 5; i++ {
+		file.Write([]byte("0123456789"))
+	}
+
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rotated, _ := filepath.Glob(path + ".*")
+
+	if len(rotated) != 2 {
+		t.Fatalf("Expected 2 retained backups, got %v", rotated)
+	}
+
+	current, _ := os.ReadFile(path)
+
+	if string(current) != "0123456789" {
+		t.Fatalf("Unexpected current file content %q", current)
+	}
+}
+
+func TestRotatingFile_IgnoresStraySiblings(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	if err := os.WriteFile(path+".bak", []byte("keep"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := OpenRotatingFile(path, RotationConfig{MaxSize: 10, MaxBackups: 2})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock := time.Unix(1000, 0)
+	file.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+
+	for i := 0; i < 5; i++ {
+		file.Write([]byte("0123456789"))
+	}
+
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(path + ".bak"); err != nil {
+		t.Fatal("Unrelated sibling removed")
+	}
+
+	rotated, _ := filepath.Glob(path + ".*")
+
+	// Two backups plus app.log.bak
+	if len(rotated) != 3 {
+		t.Fatalf("Expected 2 retained backups besides the stray file, got %v", rotated)
+	}
+}
+
+func TestRotatingFile_Compress(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	file, err := OpenRotatingFile(path, RotationConfig{Compress: true})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file.Write([]byte("line\n"))
+
+	if err := file.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+
+	file.Close()
+
+	rotated, _ := filepath.Glob(path + ".*")
+
+	if len(rotated) != 1 || !strings.HasSuffix(rotated[0], ".gz") {
+		t.Fatalf("Rotated file not compressed: %v", rotated)
+	}
+}
+
+func TestRotatingFile_Reopen(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	file, err := OpenRotatingFile(path, RotationConfig{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	// Simulate an external tool moving the file away
+	os.Rename(path, path+".moved")
+
+	if err := file.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+
+	file.Write([]byte("after\n"))
+
+	content, _ := os.ReadFile(path)
+
+	if string(content) != "after\n" {
+		t.Fatal("Writes did not go to the reopened file")
+	}
+}
+
+func TestZapLogger_Close(t *testing.T) {
+
+	logger, err := NewZapLogger(Config{
+		FileEnabled: true,
+		FilePath:    filepath.Join(t.TempDir(), "app.log"),
+		Level:       "debug",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRotatingFile_SameMillisecondRotations(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	file, err := OpenRotatingFile(path, RotationConfig{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file.now = func() time.Time {
+		return time.Unix(1000, 0)
+	}
+
+	for _, line := range []string{"first\n", "second\n"} {
+
+		file.Write([]byte(line))
+
+		if err := file.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file.Close()
+
+	rotated, _ := filepath.Glob(path + ".*")
+
+	if len(rotated) != 2 {
+		t.Fatalf("Rotations in the same millisecond collided: %v", rotated)
+	}
+}
+
+func TestRotatingFile_KeepsHandleWhenReopenFails(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	file, err := OpenRotatingFile(path, RotationConfig{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	// A directory at the path makes opening the new file fail
+	os.Rename(path, path+".moved")
+	os.Mkdir(path, 0755)
+
+	if err := file.Reopen(); err == nil {
+		t.Fatal("Expected reopen to fail")
+	}
+
+	if _, err := file.Write([]byte("kept\n")); err != nil {
+		t.Fatalf("Previous handle closed: %v", err)
+	}
+
+	content, _ := os.ReadFile(path + ".moved")
+
+	if string(content) != "kept\n" {
+		t.Fatal("Write did not reach the previous file")
+	}
+}
+
+func TestRotatingFile_DoubleClose(t *testing.T) {
+
+	file, err := OpenRotatingFile(filepath.Join(t.TempDir(), "app.log"), RotationConfig{ReopenOnSIGHUP: true})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
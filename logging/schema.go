@@ -0,0 +1,167 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// Schema selects the field names ZapLogger writes
+type Schema string
+
+const (
+	SchemaDefault Schema = ""     // code, status, trace_id, stacktrace, ...
+	SchemaECS     Schema = "ecs"  // Elastic Common Schema
+	SchemaOTel    Schema = "otel" // OpenTelemetry log data model and semantic conventions
+)
+
+// schemaKeys names each output field; an empty key omits the field
+type schemaKeys struct {
+	time    string
+	level   string
+	message string
+	caller  string
+	stack   string // zap's own call-site stack
+
+	// callerLine splits the call site: caller holds the file and callerLine the line
+	callerLine string
+	// upperLevel writes levels in upper case, e.g. "WARN"
+	upperLevel bool
+
+	code       string
+	errMessage string
+	status     string
+	errLevel   string
+	traceID    string
+	spanID     string
+	timestamp  string
+	stacktrace string
+	cause      string
+	causes     string
+	details    string
+	attributes string
+}
+
+var schemas = map[Schema]schemaKeys{
+	SchemaDefault: {
+		time:       "timestamp",
+		level:      "level",
+		message:    "msg",
+		caller:     "caller",
+		stack:      "stacktrace",
+		code:       "code",
+		status:     "status",
+		errLevel:   "level",
+		traceID:    "trace_id",
+		spanID:     "span_id",
+		timestamp:  "timestamp",
+		stacktrace: "stacktrace",
+		cause:      "error",
+		causes:     "causes",
+		details:    "details",
+		attributes: "attributes",
+	},
+	SchemaECS: {
+		time:       "@timestamp",
+		level:      "log.level",
+		message:    "message",
+		caller:     "log.origin.file.name",
+		callerLine: "log.origin.file.line",
+		code:       "error.code",
+		errMessage: "error.message",
+		status:     "http.response.status_code",
+		traceID:    "trace.id",
+		spanID:     "span.id",
+		timestamp:  "event.created",
+		stacktrace: "error.stack_trace",
+		causes:     "error.causes",
+		details:    "error.details",
+		attributes: "labels",
+	},
+	SchemaOTel: {
+		time:       "timestamp",
+		level:      "severity_text",
+		upperLevel: true,
+		message:    "body",
+		caller:     "code.filepath",
+		callerLine: "code.lineno",
+		code:       "exception.type",
+		errMessage: "exception.message",
+		status:     "http.response.status_code",
+		traceID:    "trace_id",
+		spanID:     "span_id",
+		stacktrace: "exception.stacktrace",
+		cause:      "error.cause",
+		causes:     "exception.causes",
+		details:    "error.details",
+		attributes: "attributes",
+	},
+}
+
+func schemaKeysFor(schema Schema) (schemaKeys, error) {
+
+	keys, ok := schemas[schema]
+
+	if !ok {
+		return schemaKeys{}, fmt.Errorf("unknown log schema %q", schema)
+	}
+
+	return keys, nil
+}
+
+// encoderConfig applies the schema's entry keys to config
+func (k schemaKeys) encoderConfig(config zapcore.EncoderConfig) zapcore.EncoderConfig {
+
+	config.MessageKey = k.message
+	config.CallerKey = k.caller
+	config.StacktraceKey = k.stack
+
+	// callerEncoder writes the split call site instead
+	if k.callerLine != "" {
+		config.CallerKey = ""
+	}
+
+	if k.upperLevel {
+		config.EncodeLevel = zapcore.CapitalLevelEncoder
+	}
+
+	return config
+}
+
+// wrap adds the split call site fields to entries written by encoder
+func (k schemaKeys) wrap(encoder zapcore.Encoder) zapcore.Encoder {
+
+	if k.callerLine == "" {
+		return encoder
+	}
+
+	return &callerEncoder{Encoder: encoder, keys: k}
+}
+
+// callerEncoder writes the call site as separate file and line fields
+type callerEncoder struct {
+	zapcore.Encoder
+	keys schemaKeys
+}
+
+func (e *callerEncoder) Clone() zapcore.Encoder {
+	return &callerEncoder{Encoder: e.Encoder.Clone(), keys: e.keys}
+}
+
+func (e *callerEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+
+	if entry.Caller.Defined {
+
+		file, _, _ := strings.Cut(entry.Caller.TrimmedPath(), ":")
+
+		fields = append([]zapcore.Field{
+			zap.String(e.keys.caller, file),
+			zap.Int(e.keys.callerLine, entry.Caller.Line),
+		}, fields...)
+	}
+
+	return e.Encoder.EncodeEntry(entry, fields)
+}
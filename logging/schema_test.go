@@ -0,0 +1,156 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/krisalay/error-framework/core"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func (c fixedClock) NewTicker(d time.Duration) *time.Ticker { return time.NewTicker(d) }
+
+func TestZapLogger_SchemaGolden(t *testing.T) {
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	err := core.New().
+		WithMessage("Order not found").
+		WithCode(core.CodeNotFound).
+		WithStatus(404).
+		WithLevel(core.LevelWarn).
+		WithTraceID("4bf92f3577b34da6a3ce929d0e0e4736").
+		WithSpanID("00f067aa0ba902b7").
+		WithDetails(map[string]any{"order_id": "o-1"}).
+		WithAttribute("user_id", "u-1").
+		WithInternal(errors.New("no rows")).
+		WithStackTrace("main.handler\n\tmain.go:10").
+		Build()
+
+	err.Timestamp = now
+
+	for _, schema := range []Schema{SchemaDefault, SchemaECS, SchemaOTel} {
+
+		name := string(schema)
+		if name == "" {
+			name = "default"
+		}
+
+		t.Run(name, func(t *testing.T) {
+
+			keys, keysErr := schemaKeysFor(schema)
+
+			if keysErr != nil {
+				t.Fatal(keysErr)
+			}
+
+			var buf bytes.Buffer
+
+			logger := &ZapLogger{
+				logger: zap.New(
+					zapcore.NewCore(newZapEncoder("json", keys), zapcore.AddSync(&buf), zapcore.DebugLevel),
+					zap.WithClock(fixedClock(now)),
+				),
+				schema: schema,
+			}
+
+			logger.Log(err)
+
+			golden := filepath.Join("testdata", "schema_"+name+".golden")
+
+			if *update {
+				if writeErr := os.WriteFile(golden, buf.Bytes(), 0o644); writeErr != nil {
+					t.Fatal(writeErr)
+				}
+			}
+
+			expected, readErr := os.ReadFile(golden)
+
+			if readErr != nil {
+				t.Fatal(readErr)
+			}
+
+			if !bytes.Equal(buf.Bytes(), expected) {
+				t.Fatalf("Output does not match %s\ngot:  %s\nwant: %s", golden, buf.Bytes(), expected)
+			}
+		})
+	}
+}
+
+func TestZapLogger_SchemaFields(t *testing.T) {
+
+	err := core.New().
+		WithMessage("Order not found").
+		WithCode(core.CodeNotFound).
+		WithLevel(core.LevelWarn).
+		WithInternal(errors.New("no rows")).
+		Build()
+
+	cases := map[Schema]map[string]any{
+		SchemaECS: {
+			"error.message":        "Order not found",
+			"log.origin.file.name": "logging/zap_logger.go",
+			"log.level":            "warn",
+		},
+		SchemaOTel: {
+			"exception.message": "Order not found",
+			"code.filepath":     "logging/zap_logger.go",
+			"severity_text":     "WARN",
+		},
+	}
+
+	lineKeys := map[Schema]string{SchemaECS: "log.origin.file.line", SchemaOTel: "code.lineno"}
+
+	for schema, expected := range cases {
+
+		keys, _ := schemaKeysFor(schema)
+
+		var buf bytes.Buffer
+
+		logger := &ZapLogger{
+			logger: zap.New(
+				zapcore.NewCore(newZapEncoder("json", keys), zapcore.AddSync(&buf), zapcore.DebugLevel),
+				zap.AddCaller(),
+			),
+			schema: schema,
+		}
+
+		logger.Log(err)
+
+		var entry map[string]any
+
+		if decodeErr := json.Unmarshal(buf.Bytes(), &entry); decodeErr != nil {
+			t.Fatal(decodeErr)
+		}
+
+		for key, value := range expected {
+			if entry[key] != value {
+				t.Errorf("%s: expected %s=%v, got %v", schema, key, value, entry[key])
+			}
+		}
+
+		if line, ok := entry[lineKeys[schema]].(float64); !ok || line <= 0 {
+			t.Errorf("%s: caller line missing: %v", schema, entry[lineKeys[schema]])
+		}
+	}
+}
+
+func TestNewZapLogger_UnknownSchema(t *testing.T) {
+
+	if _, err := NewZapLogger(Config{Schema: "gelf"}); err == nil {
+		t.Fatal("Expected an error for an unknown schema")
+	}
+}
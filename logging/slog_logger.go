@@ -0,0 +1,157 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/krisalay/error-framework/core"
+)
+
+// SlogLevelFatal is the slog level used for core.LevelFatal
+const SlogLevelFatal = slog.Level(12)
+
+type SlogLogger struct {
+	logger *slog.Logger
+	file   *RotatingFile
+	levels *LevelController
+
+	writeErrors atomic.Uint64
+}
+
+// NewSlogLogger logs through logger, or slog.Default() when nil
+func NewSlogLogger(logger *slog.Logger) *SlogLogger {
+
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &SlogLogger{
+		logger: logger,
+	}
+}
+
+// NewSlogLoggerFromConfig builds a slog handler for the console and file outputs of config
+func NewSlogLoggerFromConfig(config Config) (*SlogLogger, error) {
+
+	levels, err := levelsFor(config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var writers []io.Writer
+	var file *RotatingFile
+
+	if config.ConsoleEnabled {
+		writers = append(writers, os.Stdout)
+	}
+
+	if config.FileEnabled {
+
+		file, err = OpenRotatingFile(config.FilePath, config.Rotation)
+
+		if err != nil {
+			return nil, err
+		}
+
+		writers = append(writers, file)
+	}
+
+	options := &slog.HandlerOptions{
+		Level:       slog.LevelDebug, // filtered by levels in Log
+		ReplaceAttr: replaceFatalLevel,
+	}
+
+	var handler slog.Handler
+
+	if config.Encoding == "console" {
+		handler = slog.NewTextHandler(io.MultiWriter(writers...), options)
+	} else {
+		handler = slog.NewJSONHandler(io.MultiWriter(writers...), options)
+	}
+
+	logger := NewSlogLogger(slog.New(handler))
+	logger.file = file
+	logger.levels = levels
+
+	return logger, nil
+}
+
+// Close closes the log file opened from config
+func (s *SlogLogger) Close() error {
+
+	if s.file == nil {
+		return nil
+	}
+
+	return s.file.Close()
+}
+
+// Levels returns the controller built from config, or nil for NewSlogLogger
+func (s *SlogLogger) Levels() *LevelController {
+	return s.levels
+}
+
+func (s *SlogLogger) Log(err *core.AppError) {
+
+	if err == nil {
+		return
+	}
+
+	if s.levels != nil && !s.levels.Enabled(err) {
+		return
+	}
+
+	ctx := context.Background()
+	level := toSlogLevel(err.Level)
+	handler := s.logger.Handler()
+
+	if !handler.Enabled(ctx, level) {
+		return
+	}
+
+	record := slog.NewRecord(time.Now(), level, err.Message, 0)
+	record.AddAttrs(slog.Any("error", err))
+
+	// Handle directly, LogAttrs discards write errors
+	if handleErr := handler.Handle(ctx, record); handleErr != nil {
+		s.writeErrors.Add(1)
+	}
+}
+
+// WriteErrors returns the number of entries the handler failed to write
+func (s *SlogLogger) WriteErrors() uint64 {
+	return s.writeErrors.Load()
+}
+
+func toSlogLevel(level core.ErrorLevel) slog.Level {
+
+	switch level {
+	case core.LevelDebug:
+		return slog.LevelDebug
+	case core.LevelInfo:
+		return slog.LevelInfo
+	case core.LevelWarn:
+		return slog.LevelWarn
+	case core.LevelFatal:
+		return SlogLevelFatal
+	default:
+		return slog.LevelError
+	}
+}
+
+// replaceFatalLevel prints SlogLevelFatal as FATAL instead of ERROR+4
+func replaceFatalLevel(groups []string, attr slog.Attr) slog.Attr {
+
+	if attr.Key == slog.LevelKey && len(groups) == 0 {
+		if level, ok := attr.Value.Any().(slog.Level); ok && level == SlogLevelFatal {
+			attr.Value = slog.StringValue("FATAL")
+		}
+	}
+
+	return attr
+}
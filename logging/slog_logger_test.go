@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/krisalay/error-framework/core"
+)
+
+func TestSlogLogger_GroupedAttributes(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	logger := NewSlogLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: replaceFatalLevel,
+	})))
+
+	logger.Log(core.New().
+		WithMessage("failed").
+		WithCode(core.CodeDBError).
+		WithLevel(core.LevelFatal).
+		WithTraceID("trace-1").
+		WithDetail("table", "users").
+		WithInternal(errors.New("connection reset")).
+		Build())
+
+	var entry struct {
+		Level string `json:"level"`
+		Msg   string `json:"msg"`
+		Error struct {
+			Code    string         `json:"code"`
+			Status  int            `json:"status"`
+			TraceID string         `json:"trace_id"`
+			Details map[string]any `json:"details"`
+			Causes  []string       `json:"causes"`
+		} `json:"error"`
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+
+	if entry.Level != "FATAL" || entry.Msg != "failed" {
+		t.Fatalf("Unexpected entry: %s", buf.String())
+	}
+
+	if entry.Error.Code != core.CodeDBError || entry.Error.TraceID != "trace-1" || entry.Error.Status != 500 {
+		t.Fatalf("Unexpected error group: %s", buf.String())
+	}
+
+	if entry.Error.Details["table"] != "users" || len(entry.Error.Causes) != 1 {
+		t.Fatalf("Details or causes missing: %s", buf.String())
+	}
+}
+
+func TestAppError_LogValue(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("x", "err", core.New().WithCode(core.CodeNotFound).Build())
+
+	var entry struct {
+		Err map[string]any `json:"err"`
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+
+	if entry.Err["code"] != core.CodeNotFound {
+		t.Fatalf("AppError not logged as structured value: %s", buf.String())
+	}
+}
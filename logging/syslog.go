@@ -0,0 +1,391 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SyslogSDID names the structured data element carrying error fields
+const SyslogSDID = "apperror@32473"
+
+const (
+	defaultSyslogAddress = "/dev/log"
+	syslogFacilityUser   = 1
+	syslogTimeFormat     = "2006-01-02T15:04:05.000000Z07:00"
+
+	syslogDialTimeout  = 5 * time.Second
+	syslogWriteTimeout = 2 * time.Second
+
+	// Redialing gives up after syslogMaxRedials attempts until the next failed write
+	syslogMaxRedials     = 8
+	syslogRedialBackoff  = 100 * time.Millisecond
+	syslogMaxRedialDelay = 10 * time.Second
+)
+
+var errSyslogDisconnected = errors.New("syslog: not connected")
+
+type SyslogConfig struct {
+	Enabled bool
+	Network string // unixgram, unix, udp or tcp; empty uses the local daemon
+	Address string // defaults to /dev/log, which journald also serves
+	AppName string // defaults to the executable name
+
+	// Facility is the RFC 5424 facility code, 0 defaults to user (1)
+	Facility int
+}
+
+// syslogWriter sends RFC 5424 messages.
+// After a failed write it redials in the background; messages written meanwhile fail fast.
+type syslogWriter struct {
+	config   SyslogConfig
+	hostname string
+	procID   string
+	backoff  time.Duration
+
+	mu          sync.Mutex
+	conn        net.Conn
+	redialing   bool
+	closed      bool
+	stop        chan struct{}
+	redialsDone sync.WaitGroup
+}
+
+func dialSyslog(config SyslogConfig) (*syslogWriter, error) {
+
+	if config.Address == "" {
+		config.Address = defaultSyslogAddress
+	}
+
+	if config.AppName == "" {
+		config.AppName = filepath.Base(os.Args[0])
+	}
+
+	if config.Facility == 0 {
+		config.Facility = syslogFacilityUser
+	}
+
+	hostname, _ := os.Hostname()
+
+	w := &syslogWriter{
+		config:   config,
+		hostname: hostname,
+		procID:   strconv.Itoa(os.Getpid()),
+		backoff:  syslogRedialBackoff,
+		stop:     make(chan struct{}),
+	}
+
+	conn, err := w.dial()
+
+	if err != nil {
+		return nil, err
+	}
+
+	w.conn = conn
+
+	return w, nil
+}
+
+func (w *syslogWriter) dial() (net.Conn, error) {
+
+	if w.config.Network != "" {
+
+		conn, err := net.DialTimeout(w.config.Network, w.config.Address, syslogDialTimeout)
+
+		if err != nil {
+			return nil, fmt.Errorf("syslog: %w", err)
+		}
+
+		return conn, nil
+	}
+
+	// Local daemons listen on datagram or stream sockets
+	var errs []error
+
+	for _, network := range []string{"unixgram", "unix"} {
+
+		conn, err := net.DialTimeout(network, w.config.Address, syslogDialTimeout)
+
+		if err == nil {
+			return conn, nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	return nil, fmt.Errorf("syslog: %w", errors.Join(errs...))
+}
+
+func (w *syslogWriter) stream() bool {
+	return w.config.Network == "tcp" || w.config.Network == "tcp4" || w.config.Network == "tcp6" || w.config.Network == "unix"
+}
+
+// format builds an RFC 5424 message, octet-counted for stream transports (RFC 6587)
+func (w *syslogWriter) format(severity int, t time.Time, msgID string, params [][2]string, msg string) []byte {
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %s %s ",
+		w.config.Facility*8+severity,
+		t.Format(syslogTimeFormat),
+		syslogHeaderValue(w.hostname),
+		syslogHeaderValue(w.config.AppName),
+		w.procID,
+		syslogHeaderValue(msgID),
+	)
+
+	if len(params) == 0 {
+		b.WriteString("-")
+	} else {
+
+		b.WriteString("[" + SyslogSDID)
+
+		for _, param := range params {
+			fmt.Fprintf(&b, ` %s="%s"`, param[0], syslogParamEscaper.Replace(param[1]))
+		}
+
+		b.WriteString("]")
+	}
+
+	if msg != "" {
+		b.WriteString(" " + msg)
+	}
+
+	if w.stream() {
+		return []byte(strconv.Itoa(b.Len()) + " " + b.String())
+	}
+
+	return []byte(b.String())
+}
+
+func (w *syslogWriter) write(message []byte) error {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		w.disconnect()
+		return errSyslogDisconnected
+	}
+
+	w.conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout))
+
+	if _, err := w.conn.Write(message); err != nil {
+		w.disconnect()
+		return fmt.Errorf("syslog: %w", err)
+	}
+
+	return nil
+}
+
+// disconnect drops the connection and starts redialing, the caller holds w.mu
+func (w *syslogWriter) disconnect() {
+
+	if w.conn != nil {
+		w.conn.Close()
+		w.conn = nil
+	}
+
+	if w.redialing || w.closed {
+		return
+	}
+
+	w.redialing = true
+	w.redialsDone.Add(1)
+
+	go w.redial()
+}
+
+// redial reconnects with exponential backoff, giving up after syslogMaxRedials attempts
+func (w *syslogWriter) redial() {
+
+	defer w.redialsDone.Done()
+
+	delay := w.backoff
+
+	for attempt := 0; attempt < syslogMaxRedials; attempt++ {
+
+		select {
+		case <-time.After(delay):
+		case <-w.stop:
+			w.finishRedial(nil)
+			return
+		}
+
+		if conn, err := w.dial(); err == nil {
+			w.finishRedial(conn)
+			return
+		}
+
+		delay = min(delay*2, syslogMaxRedialDelay)
+	}
+
+	w.finishRedial(nil)
+}
+
+func (w *syslogWriter) finishRedial(conn net.Conn) {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.redialing = false
+
+	if conn == nil {
+		return
+	}
+
+	if w.closed {
+		conn.Close()
+		return
+	}
+
+	w.conn = conn
+}
+
+func (w *syslogWriter) Close() error {
+
+	w.mu.Lock()
+
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+
+	w.closed = true
+	close(w.stop)
+
+	var err error
+
+	if w.conn != nil {
+		err = w.conn.Close()
+		w.conn = nil
+	}
+
+	w.mu.Unlock()
+
+	w.redialsDone.Wait()
+
+	return err
+}
+
+var syslogParamEscaper = strings.NewReplacer(`"`, `\"`, `\`, `\\`, `]`, `\]`)
+
+// syslogHeaderValue replaces empty values with the nil value and strips spaces
+func syslogHeaderValue(value string) string {
+
+	if value == "" {
+		return "-"
+	}
+
+	return strings.ReplaceAll(value, " ", "_")
+}
+
+// syslogSeverity maps zap levels, which ZapLogger derives from core.ErrorLevel
+func syslogSeverity(level zapcore.Level) int {
+
+	switch level {
+	case zapcore.DebugLevel:
+		return 7 // debug
+	case zapcore.InfoLevel:
+		return 6 // informational
+	case zapcore.WarnLevel:
+		return 4 // warning
+	case zapcore.ErrorLevel:
+		return 3 // error
+	default:
+		return 2 // critical
+	}
+}
+
+// syslogCore is a zap core writing entries to syslog.
+// Code, status and trace fields become structured data; the encoded entry is the message.
+type syslogCore struct {
+	zapcore.LevelEnabler
+	encoder zapcore.Encoder
+	writer  *syslogWriter
+	keys    schemaKeys
+	fields  []zapcore.Field
+}
+
+func newSyslogCore(writer *syslogWriter, keys schemaKeys, enabler zapcore.LevelEnabler) *syslogCore {
+
+	encoderConfig := keys.encoderConfig(zap.NewProductionEncoderConfig())
+	encoderConfig.TimeKey = ""
+	encoderConfig.LevelKey = ""
+
+	return &syslogCore{
+		LevelEnabler: enabler,
+		encoder:      keys.wrap(zapcore.NewJSONEncoder(encoderConfig)),
+		writer:       writer,
+		keys:         keys,
+	}
+}
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+
+	clone := *c
+	clone.fields = append(append([]zapcore.Field(nil), c.fields...), fields...)
+
+	return &clone
+}
+
+func (c *syslogCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+
+	return checked
+}
+
+func (c *syslogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+
+	all := append(append([]zapcore.Field(nil), c.fields...), fields...)
+
+	buf, err := c.encoder.EncodeEntry(entry, all)
+
+	if err != nil {
+		return err
+	}
+
+	defer buf.Free()
+
+	var msgID string
+	var params [][2]string
+
+	for _, field := range all {
+
+		switch field.Key {
+		case "":
+			continue
+		case c.keys.code:
+			msgID = field.String
+			params = append(params, [2]string{"code", field.String})
+		case c.keys.status:
+			params = append(params, [2]string{"status", strconv.FormatInt(field.Integer, 10)})
+		case c.keys.traceID:
+			if field.String != "" {
+				params = append(params, [2]string{"trace_id", field.String})
+			}
+		case c.keys.spanID:
+			params = append(params, [2]string{"span_id", field.String})
+		}
+	}
+
+	message := strings.TrimSuffix(buf.String(), "\n")
+
+	return c.writer.write(c.writer.format(syslogSeverity(entry.Level), entry.Time, msgID, params, message))
+}
+
+func (c *syslogCore) Sync() error {
+	return nil
+}
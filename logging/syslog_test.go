@@ -0,0 +1,246 @@
+package logging
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krisalay/error-framework/core"
+	"go.uber.org/zap/zapcore"
+)
+
+var syslogPattern = regexp.MustCompile(`^<(\d+)>1 \S+ \S+ api \d+ NOT_FOUND \[apperror@32473 code="NOT_FOUND" status="404" trace_id="trace-1"\] \{.*"msg":"Order not found".*\}$`)
+
+func notFoundError() *core.AppError {
+
+	return core.New().
+		WithMessage("Order not found").
+		WithCode(core.CodeNotFound).
+		WithStatus(404).
+		WithLevel(core.LevelWarn).
+		WithTraceID("trace-1").
+		Build()
+}
+
+func newSyslogTestLogger(t *testing.T, network, address string) *ZapLogger {
+
+	logger, err := NewZapLogger(Config{
+		Level: "debug",
+		Syslog: SyslogConfig{
+			Enabled: true,
+			Network: network,
+			Address: address,
+			AppName: "api",
+		},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { logger.Close() })
+
+	return logger
+}
+
+func assertSyslogMessage(t *testing.T, message string) {
+
+	t.Helper()
+
+	match := syslogPattern.FindStringSubmatch(message)
+
+	if match == nil {
+		t.Fatalf("Unexpected syslog message:\n%s", message)
+	}
+
+	// user facility (1) * 8 + warning (4)
+	if match[1] != "12" {
+		t.Fatal("Unexpected priority", match[1])
+	}
+}
+
+func readPacket(t *testing.T, conn net.PacketConn) string {
+
+	t.Helper()
+
+	buf := make([]byte, 64*1024)
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	n, _, err := conn.ReadFrom(buf)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(buf[:n])
+}
+
+func TestSyslog_UDP(t *testing.T) {
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	newSyslogTestLogger(t, "udp", conn.LocalAddr().String()).Log(notFoundError())
+
+	assertSyslogMessage(t, readPacket(t, conn))
+}
+
+func TestSyslog_UnixDatagram(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "log.sock")
+
+	conn, err := net.ListenPacket("unixgram", path)
+
+	if err != nil {
+		t.Skip("unix datagram sockets unavailable:", err)
+	}
+
+	defer conn.Close()
+
+	// An empty network probes the local daemon's socket types
+	newSyslogTestLogger(t, "", path).Log(notFoundError())
+
+	assertSyslogMessage(t, readPacket(t, conn))
+}
+
+func TestSyslog_TCPOctetCounting(t *testing.T) {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer listener.Close()
+
+	received := make(chan string, 1)
+
+	go func() {
+
+		conn, err := listener.Accept()
+
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+
+		length, _ := reader.ReadString(' ')
+		n, err := strconv.Atoi(strings.TrimSpace(length))
+
+		if err != nil {
+			return
+		}
+
+		message := make([]byte, n)
+
+		if _, err := io.ReadFull(reader, message); err == nil {
+			received <- string(message)
+		}
+	}()
+
+	newSyslogTestLogger(t, "tcp", listener.Addr().String()).Log(notFoundError())
+
+	select {
+	case message := <-received:
+		assertSyslogMessage(t, message)
+	case <-time.After(2 * time.Second):
+		t.Fatal("No message received")
+	}
+}
+
+func TestSyslogSeverity(t *testing.T) {
+
+	// ZapLogger logs core levels at the zap level of the same name
+	levels := map[zapcore.Level]int{
+		zapcore.DebugLevel: 7,
+		zapcore.InfoLevel:  6,
+		zapcore.WarnLevel:  4,
+		zapcore.ErrorLevel: 3,
+		zapcore.FatalLevel: 2,
+	}
+
+	for level, severity := range levels {
+		if got := syslogSeverity(level); got != severity {
+			t.Errorf("%s: expected severity %d, got %d", level, severity, got)
+		}
+	}
+}
+
+func TestSyslog_RedialsInBackground(t *testing.T) {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer listener.Close()
+
+	accepted := make(chan net.Conn, 2)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+
+			if err != nil {
+				return
+			}
+
+			accepted <- conn
+		}
+	}()
+
+	writer, err := dialSyslog(SyslogConfig{Network: "tcp", Address: listener.Addr().String()})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer writer.Close()
+
+	writer.backoff = 50 * time.Millisecond
+
+	(<-accepted).Close()
+
+	// Simulate a failed write
+	writer.mu.Lock()
+	writer.disconnect()
+	writer.mu.Unlock()
+
+	if err := writer.write([]byte("dropped")); err != errSyslogDisconnected {
+		t.Fatalf("Expected a fast failure while redialing, got %v", err)
+	}
+
+	select {
+	case conn := <-accepted:
+		defer conn.Close()
+	case <-time.After(2 * time.Second):
+		t.Fatal("Writer did not redial")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+
+	for writer.write([]byte("delivered")) != nil {
+
+		if time.Now().After(deadline) {
+			t.Fatal("Writes did not resume after redialing")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -1,7 +1,9 @@
 package logging
 
 import (
+	"errors"
 	"os"
+	"sync/atomic"
 
 	"github.com/krisalay/error-framework/core"
 	"go.uber.org/zap"
@@ -10,25 +12,34 @@ import (
 
 type ZapLogger struct {
 	logger *zap.Logger
+	file   *RotatingFile
+	syslog *syslogWriter
+	levels *LevelController
+	schema Schema
+	errors *errorCounter
 }
 
 func NewZapLogger(config Config) (*ZapLogger, error) {
 
-	level := parseLevel(config.Level)
+	levels, err := levelsFor(config)
 
-	encoderConfig := zap.NewProductionEncoderConfig()
-	encoderConfig.TimeKey = "timestamp"
-	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	if err != nil {
+		return nil, err
+	}
 
-	var encoder zapcore.Encoder
+	keys, err := schemaKeysFor(config.Schema)
 
-	if config.Encoding == "console" {
-		encoder = zapcore.NewConsoleEncoder(encoderConfig)
-	} else {
-		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	if err != nil {
+		return nil, err
 	}
 
+	// Cores accept every level; filtering happens in Log so levels can change at runtime
+	level := zapcore.DebugLevel
+
+	encoder := newZapEncoder(config.Encoding, keys)
+
 	var cores []zapcore.Core
+	var file *RotatingFile
 
 	// Console logging
 	if config.ConsoleEnabled {
@@ -43,11 +54,7 @@ func NewZapLogger(config Config) (*ZapLogger, error) {
 	// File logging
 	if config.FileEnabled {
 
-		file, err := os.OpenFile(
-			config.FilePath,
-			os.O_APPEND|os.O_CREATE|os.O_WRONLY,
-			0644,
-		)
+		file, err = OpenRotatingFile(config.FilePath, config.Rotation)
 
 		if err != nil {
 			return nil, err
@@ -55,52 +62,133 @@ func NewZapLogger(config Config) (*ZapLogger, error) {
 
 		fileCore := zapcore.NewCore(
 			encoder,
-			zapcore.AddSync(file),
+			file,
 			level,
 		)
 
 		cores = append(cores, fileCore)
 	}
 
+	// Syslog
+	var syslog *syslogWriter
+
+	if config.Syslog.Enabled {
+
+		syslog, err = dialSyslog(config.Syslog)
+
+		if err != nil {
+			if file != nil {
+				file.Close()
+			}
+			return nil, err
+		}
+
+		cores = append(cores, newSyslogCore(syslog, keys, level))
+	}
+
 	coreCombined := zapcore.NewTee(cores...)
 
+	errorOutput := &errorCounter{out: zapcore.Lock(os.Stderr)}
+
 	logger := zap.New(
 		coreCombined,
 		zap.AddCaller(),
 		zap.AddStacktrace(zapcore.ErrorLevel),
+		zap.WithFatalHook(continueAfterFatal{}),
+		zap.ErrorOutput(errorOutput),
 	)
 
 	return &ZapLogger{
 		logger: logger,
+		file:   file,
+		syslog: syslog,
+		levels: levels,
+		schema: config.Schema,
+		errors: errorOutput,
 	}, nil
 }
 
-func (z *ZapLogger) Log(err *core.AppError) {
+// WriteErrors returns the number of entries a core failed to write
+func (z *ZapLogger) WriteErrors() uint64 {
 
-	if err == nil {
-		return
+	if z.errors == nil {
+		return 0
 	}
 
-	fields := []zap.Field{
-		zap.String("code", err.Code),
-		zap.Int("status", err.Status),
-		zap.String("level", err.Level.String()),
-		zap.String("trace_id", err.TraceID),
-		zap.Time("timestamp", err.Timestamp),
+	return z.errors.count.Load()
+}
+
+// errorCounter receives zap's internal errors, one message per failed write
+type errorCounter struct {
+	count atomic.Uint64
+	out   zapcore.WriteSyncer
+}
+
+func (c *errorCounter) Write(p []byte) (int, error) {
+	c.count.Add(1)
+	return c.out.Write(p)
+}
+
+func (c *errorCounter) Sync() error {
+	return c.out.Sync()
+}
+
+func newZapEncoder(encoding string, keys schemaKeys) zapcore.Encoder {
+
+	encoderConfig := keys.encoderConfig(zap.NewProductionEncoderConfig())
+	encoderConfig.TimeKey = keys.time
+	encoderConfig.LevelKey = keys.level
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	if encoding == "console" {
+		return keys.wrap(zapcore.NewConsoleEncoder(encoderConfig))
 	}
 
-	if err.StackTrace != "" {
-		fields = append(fields, zap.String("stacktrace", err.StackTrace))
+	return keys.wrap(zapcore.NewJSONEncoder(encoderConfig))
+}
+
+// Levels returns the controller deciding which errors are logged
+func (z *ZapLogger) Levels() *LevelController {
+	return z.levels
+}
+
+// Close flushes buffered entries and closes the log file and syslog connection
+func (z *ZapLogger) Close() error {
+
+	// Syncing stdout fails on some platforms, so only the file result matters
+	z.logger.Sync()
+
+	var errs []error
+
+	if z.file != nil {
+		errs = append(errs, z.file.Close())
 	}
 
-	if err.Err != nil {
-		fields = append(fields, zap.Error(err.Err))
+	if z.syslog != nil {
+		errs = append(errs, z.syslog.Close())
 	}
 
-	if err.Details != nil {
-		fields = append(fields, zap.Any("details", err.Details))
+	return errors.Join(errs...)
+}
+
+// continueAfterFatal keeps zap from exiting on fatal entries.
+// Whether the process exits is decided by core.FatalPolicy.
+type continueAfterFatal struct{}
+
+func (continueAfterFatal) OnWrite(*zapcore.CheckedEntry, []zap.Field) {}
+
+func (z *ZapLogger) Log(err *core.AppError) {
+
+	if err == nil {
+		return
 	}
 
+	if z.levels != nil && !z.levels.Enabled(err) {
+		return
+	}
+
+	fields := z.fields(err)
+
 	switch err.Level {
 
 	case core.LevelDebug:
@@ -122,3 +210,46 @@ func (z *ZapLogger) Log(err *core.AppError) {
 		z.logger.Error(err.Message, fields...)
 	}
 }
+
+func (z *ZapLogger) fields(err *core.AppError) []zap.Field {
+
+	keys := schemas[z.schema]
+
+	var fields []zap.Field
+
+	add := func(key string, field func(key string) zap.Field) {
+		if key != "" {
+			fields = append(fields, field(key))
+		}
+	}
+
+	add(keys.code, func(key string) zap.Field { return zap.String(key, err.Code) })
+	add(keys.errMessage, func(key string) zap.Field { return zap.String(key, err.Message) })
+	add(keys.status, func(key string) zap.Field { return zap.Int(key, err.Status) })
+	add(keys.errLevel, func(key string) zap.Field { return zap.String(key, err.Level.String()) })
+	add(keys.traceID, func(key string) zap.Field { return zap.String(key, err.TraceID) })
+	add(keys.timestamp, func(key string) zap.Field { return zap.Time(key, err.Timestamp) })
+
+	if err.SpanID != "" {
+		add(keys.spanID, func(key string) zap.Field { return zap.String(key, err.SpanID) })
+	}
+
+	if err.StackTrace != "" {
+		add(keys.stacktrace, func(key string) zap.Field { return zap.String(key, err.StackTrace) })
+	}
+
+	if err.Err != nil {
+		add(keys.cause, func(key string) zap.Field { return zap.NamedError(key, err.Err) })
+		add(keys.causes, func(key string) zap.Field { return zap.Strings(key, err.Causes()) })
+	}
+
+	if err.Details != nil {
+		add(keys.details, func(key string) zap.Field { return zap.Any(key, err.Details) })
+	}
+
+	if len(err.Attributes) > 0 {
+		add(keys.attributes, func(key string) zap.Field { return zap.Any(key, err.Attributes) })
+	}
+
+	return fields
+}
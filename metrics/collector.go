@@ -0,0 +1,169 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/krisalay/error-framework/core"
+)
+
+type requestKey struct{}
+
+type labels struct {
+	code   string
+	status string
+	level  string
+	domain string
+}
+
+// Collector counts handled errors and exposes them in the Prometheus text format.
+// It implements core.Observer and core.PanicObserver.
+type Collector struct {
+	config Config
+
+	mu      sync.Mutex
+	errors  map[labels]uint64
+	codes   map[string]struct{}
+	domains map[string]struct{}
+	panics  uint64
+
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+func NewCollector(config Config) *Collector {
+
+	config = config.withDefaults()
+
+	// +Inf is always emitted, so it is not kept as a configured bucket
+	var buckets []float64
+	for _, upper := range config.Buckets {
+		if !math.IsInf(upper, 1) {
+			buckets = append(buckets, upper)
+		}
+	}
+	sort.Float64s(buckets)
+	config.Buckets = buckets
+
+	return &Collector{
+		config:  config,
+		errors:  make(map[labels]uint64),
+		codes:   make(map[string]struct{}),
+		domains: make(map[string]struct{}),
+		buckets: make([]uint64, len(buckets)),
+	}
+}
+
+// Observe records a handled error
+func (c *Collector) Observe(ctx context.Context, err *core.AppError) {
+
+	if err == nil {
+		return
+	}
+
+	if ctx != nil {
+		if counter, ok := ctx.Value(requestKey{}).(*atomic.Int64); ok {
+			counter.Add(1)
+		}
+	}
+
+	domain := err.Domain
+	if domain == "" {
+		domain = core.DomainForCode(err.Code)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := labels{
+		code:   bound(c.codes, err.Code, c.config.MaxCodes),
+		status: statusLabel(err.Status),
+		level:  err.Level.String(),
+		domain: bound(c.domains, domain, c.config.MaxDomains),
+	}
+
+	c.errors[key]++
+}
+
+// ObservePanic records a recovered panic
+func (c *Collector) ObservePanic(ctx context.Context, err *core.AppError, recovered any) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.panics++
+}
+
+// Track starts counting errors for a request.
+// The returned function records the request in the errors-per-request histogram.
+// It takes the error the request ends with; one that has not been handled yet,
+// and so will be counted when the HTTP error handler runs, is included.
+func (c *Collector) Track(ctx context.Context) (context.Context, func(err error)) {
+
+	counter := &atomic.Int64{}
+
+	return context.WithValue(ctx, requestKey{}, counter), func(err error) {
+
+		count := counter.Load()
+
+		if err != nil && !handled(err) {
+			count++
+		}
+
+		c.observeRequest(float64(count))
+	}
+}
+
+// handled reports whether err is, or wraps, an AppError the manager already handled
+func handled(err error) bool {
+
+	var appErr *core.AppError
+
+	return errors.As(err, &appErr) && appErr.IsHandled()
+}
+
+func (c *Collector) observeRequest(value float64) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, upper := range c.config.Buckets {
+		if value <= upper {
+			c.buckets[i]++
+		}
+	}
+
+	c.sum += value
+	c.count++
+}
+
+// bound returns value while the label has capacity, OverflowLabel afterwards
+func bound(seen map[string]struct{}, value string, limit int) string {
+
+	if _, ok := seen[value]; ok {
+		return value
+	}
+
+	if len(seen) >= limit {
+		return OverflowLabel
+	}
+
+	seen[value] = struct{}{}
+
+	return value
+}
+
+func statusLabel(status int) string {
+
+	if status < 100 || status > 599 {
+		return OverflowLabel
+	}
+
+	return strconv.Itoa(status)
+}
@@ -0,0 +1,94 @@
+package metrics_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/krisalay/error-framework/core"
+	"github.com/krisalay/error-framework/errtest"
+	"github.com/krisalay/error-framework/metrics"
+)
+
+func TestCollector_CountsHandledErrors(t *testing.T) {
+
+	collector := metrics.NewCollector(metrics.Config{})
+
+	manager := core.NewManager(core.ManagerConfig{
+		Logger:    errtest.NewRecorder(),
+		Observers: []core.Observer{collector},
+	})
+
+	ctx, done := collector.Track(context.Background())
+
+	manager.Handle(ctx, core.New().WithCode(core.CodeDBError).Build())
+	manager.Handle(ctx, core.New().WithCode(core.CodeDBError).Build())
+	manager.HandlePanic(ctx, "boom")
+
+	done(nil)
+
+	rec := httptest.NewRecorder()
+	collector.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := rec.Body.String()
+
+	expected := []string{
+		`errorframework_errors_total{code="DB_ERROR",domain="database",level="ERROR",status="500"} 2`,
+		`errorframework_errors_total{code="INTERNAL_ERROR",domain="generic",level="FATAL",status="500"} 1`,
+		`errorframework_errors_per_request_bucket{le="3"} 1`,
+		`errorframework_errors_per_request_bucket{le="2"} 0`,
+		`errorframework_errors_per_request_sum 3`,
+		`errorframework_panics_total 1`,
+	}
+
+	for _, line := range expected {
+		if !strings.Contains(body, line+"\n") {
+			t.Fatalf("Missing %q in:\n%s", line, body)
+		}
+	}
+}
+
+func TestCollector_TrackCountsPendingError(t *testing.T) {
+
+	collector := metrics.NewCollector(metrics.Config{})
+
+	manager := core.NewManager(core.ManagerConfig{
+		Logger:    errtest.NewRecorder(),
+		Observers: []core.Observer{collector},
+	})
+
+	ctx, done := collector.Track(context.Background())
+
+	handledErr := manager.Handle(ctx, core.New().Build())
+
+	// Already counted by the observer
+	done(handledErr)
+
+	_, done = collector.Track(context.Background())
+
+	// Returned to the HTTP error handler, which handles it after the request is recorded
+	done(core.New().Build())
+
+	rec := httptest.NewRecorder()
+	collector.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if body := rec.Body.String(); !strings.Contains(body, "errorframework_errors_per_request_sum 2\n") {
+		t.Fatalf("Unexpected histogram:\n%s", body)
+	}
+}
+
+func TestCollector_BoundsCodeCardinality(t *testing.T) {
+
+	collector := metrics.NewCollector(metrics.Config{MaxCodes: 1})
+
+	collector.Observe(context.Background(), core.New().WithCode("FIRST").Build())
+	collector.Observe(context.Background(), core.New().WithCode("SECOND").Build())
+
+	var b strings.Builder
+	collector.WriteTo(&b)
+
+	if !strings.Contains(b.String(), `code="OTHER"`) {
+		t.Fatal("Overflowing codes not folded")
+	}
+}
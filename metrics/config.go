@@ -0,0 +1,40 @@
+package metrics
+
+type Config struct {
+	Namespace  string    // metric name prefix, defaults to "errorframework"
+	MaxCodes   int       // distinct code label values before folding into "OTHER"
+	MaxDomains int       // distinct domain label values before folding into "OTHER"
+	Buckets    []float64 // upper bounds of the errors-per-request histogram
+}
+
+const (
+	defaultNamespace  = "errorframework"
+	defaultMaxCodes   = 100
+	defaultMaxDomains = 20
+
+	// OverflowLabel replaces label values once a label reaches its cardinality limit
+	OverflowLabel = "OTHER"
+)
+
+var defaultBuckets = []float64{0, 1, 2, 3, 5, 10, 20}
+
+func (c Config) withDefaults() Config {
+
+	if c.Namespace == "" {
+		c.Namespace = defaultNamespace
+	}
+
+	if c.MaxCodes <= 0 {
+		c.MaxCodes = defaultMaxCodes
+	}
+
+	if c.MaxDomains <= 0 {
+		c.MaxDomains = defaultMaxDomains
+	}
+
+	if len(c.Buckets) == 0 {
+		c.Buckets = defaultBuckets
+	}
+
+	return c
+}
@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Handler returns an http.Handler serving the collected metrics
+func (c *Collector) Handler() http.Handler {
+	return c
+}
+
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	var buf bytes.Buffer
+
+	c.WriteTo(&buf)
+
+	w.Header().Set("Content-Type", contentType)
+	w.Write(buf.Bytes())
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var b strings.Builder
+
+	ns := c.config.Namespace
+
+	// Errors by label set
+	fmt.Fprintf(&b, "# HELP %s_errors_total Handled errors by code, status, level and domain.\n", ns)
+	fmt.Fprintf(&b, "# TYPE %s_errors_total counter\n", ns)
+
+	keys := make([]labels, 0, len(c.errors))
+	for key := range c.errors {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s_errors_total{%s} %d\n", ns, key.String(), c.errors[key])
+	}
+
+	// Errors per request
+	fmt.Fprintf(&b, "# HELP %s_errors_per_request Number of errors handled per request.\n", ns)
+	fmt.Fprintf(&b, "# TYPE %s_errors_per_request histogram\n", ns)
+
+	for i, upper := range c.config.Buckets {
+		fmt.Fprintf(&b, "%s_errors_per_request_bucket{le=\"%s\"} %d\n", ns, formatFloat(upper), c.buckets[i])
+	}
+
+	fmt.Fprintf(&b, "%s_errors_per_request_bucket{le=\"+Inf\"} %d\n", ns, c.count)
+	fmt.Fprintf(&b, "%s_errors_per_request_sum %s\n", ns, formatFloat(c.sum))
+	fmt.Fprintf(&b, "%s_errors_per_request_count %d\n", ns, c.count)
+
+	// Panics
+	fmt.Fprintf(&b, "# HELP %s_panics_total Recovered panics.\n", ns)
+	fmt.Fprintf(&b, "# TYPE %s_panics_total counter\n", ns)
+	fmt.Fprintf(&b, "%s_panics_total %d\n", ns, c.panics)
+
+	n, err := io.WriteString(w, b.String())
+
+	return int64(n), err
+}
+
+func (l labels) String() string {
+	return fmt.Sprintf(
+		"code=\"%s\",domain=\"%s\",level=\"%s\",status=\"%s\"",
+		escape(l.code),
+		escape(l.domain),
+		escape(l.level),
+		escape(l.status),
+	)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func formatFloat(value float64) string {
+
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IDGenerator creates trace IDs for requests that arrive without one
+type IDGenerator interface {
+	NewTraceID() string
+}
+
+// IDGeneratorFunc adapts a function to IDGenerator
+type IDGeneratorFunc func() string
+
+func (f IDGeneratorFunc) NewTraceID() string {
+	return f()
+}
+
+// UUIDv4Generator creates random UUIDs, the default
+type UUIDv4Generator struct{}
+
+func (UUIDv4Generator) NewTraceID() string {
+	return uuid.New().String()
+}
+
+// UUIDv7Generator creates time-ordered UUIDs.
+// Like the other generators, it panics when randomness is unavailable rather than lose the ordering.
+type UUIDv7Generator struct{}
+
+func (UUIDv7Generator) NewTraceID() string {
+
+	id, err := uuid.NewV7()
+
+	if err != nil {
+		panic(fmt.Errorf("trace ID generator: %w", err))
+	}
+
+	return id.String()
+}
+
+// ULIDGenerator creates time-ordered ULIDs in Crockford base32.
+// W3CTraceID converts them to the hex of their 16 bytes for traceparent and b3.
+type ULIDGenerator struct {
+	Now func() time.Time // defaults to time.Now
+}
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+func (g ULIDGenerator) NewTraceID() string {
+
+	now := time.Now
+	if g.Now != nil {
+		now = g.Now
+	}
+
+	var id [16]byte
+
+	// 48-bit millisecond timestamp followed by 80 random bits
+	var timestamp [8]byte
+	binary.BigEndian.PutUint64(timestamp[:], uint64(now().UnixMilli()))
+	copy(id[:6], timestamp[2:])
+	randomBytes(id[6:])
+
+	// 128 bits as 26 characters of 5 bits, the first holding 3 bits
+	var encoded [26]byte
+
+	for i := range encoded {
+
+		var value byte
+
+		for bit := i*5 - 2; bit < i*5+3; bit++ {
+
+			value <<= 1
+
+			if bit >= 0 {
+				value |= (id[bit/8] >> (7 - bit%8)) & 1
+			}
+		}
+
+		encoded[i] = crockfordAlphabet[value]
+	}
+
+	return string(encoded[:])
+}
+
+// decodeULID returns the 16 bytes of a Crockford base32 ULID
+func decodeULID(ulid string) ([16]byte, bool) {
+
+	var id [16]byte
+
+	if len(ulid) != 26 {
+		return id, false
+	}
+
+	ulid = strings.ToUpper(ulid)
+
+	// The first character holds only 3 bits
+	if ulid[0] > '7' {
+		return id, false
+	}
+
+	for i := 0; i < len(ulid); i++ {
+
+		value := strings.IndexByte(crockfordAlphabet, ulid[i])
+
+		if value < 0 {
+			return id, false
+		}
+
+		for bit := 0; bit < 5; bit++ {
+
+			position := i*5 - 2 + bit
+
+			if position < 0 || value&(1<<(4-bit)) == 0 {
+				continue
+			}
+
+			id[position/8] |= 1 << (7 - position%8)
+		}
+	}
+
+	return id, true
+}
+
+// randomBytes fills b from crypto/rand. Without randomness, IDs could collide
+// across requests, so a failing source is fatal, as with uuid.New.
+func randomBytes(b []byte) {
+
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Errorf("trace ID generator: %w", err))
+	}
+}
+
+// W3CGenerator creates 16-byte random IDs in lowercase hex, usable in traceparent as-is
+type W3CGenerator struct{}
+
+func (W3CGenerator) NewTraceID() string {
+
+	var id [16]byte
+
+	for {
+		randomBytes(id[:])
+
+		if id != [16]byte{} {
+			return hex.EncodeToString(id[:])
+		}
+	}
+}
+
+// DeterministicGenerator returns seed+1, seed+2, ... as 32 hex characters, for tests
+type DeterministicGenerator struct {
+	counter atomic.Uint64
+}
+
+func NewDeterministicGenerator(seed uint64) *DeterministicGenerator {
+
+	g := &DeterministicGenerator{}
+	g.counter.Store(seed)
+
+	return g
+}
+
+func (g *DeterministicGenerator) NewTraceID() string {
+	return fmt.Sprintf("%032x", g.counter.Add(1))
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestGenerators(t *testing.T) {
+
+	cases := map[string]struct {
+		generator IDGenerator
+		pattern   *regexp.Regexp
+	}{
+		"uuidv4": {UUIDv4Generator{}, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)},
+		"uuidv7": {UUIDv7Generator{}, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)},
+		"ulid":   {ULIDGenerator{}, regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)},
+		"w3c":    {W3CGenerator{}, regexp.MustCompile(`^[0-9a-f]{32}$`)},
+	}
+
+	for name, c := range cases {
+
+		first, second := c.generator.NewTraceID(), c.generator.NewTraceID()
+
+		if !c.pattern.MatchString(first) {
+			t.Errorf("%s: malformed ID %q", name, first)
+		}
+
+		if first == second {
+			t.Errorf("%s: duplicate IDs", name)
+		}
+	}
+}
+
+func TestULIDGenerator_TimeOrdered(t *testing.T) {
+
+	now := time.UnixMilli(1714564800000)
+
+	generator := ULIDGenerator{Now: func() time.Time { return now }}
+
+	first := generator.NewTraceID()
+
+	now = now.Add(time.Millisecond)
+
+	second := generator.NewTraceID()
+
+	// The first 10 characters encode the millisecond timestamp
+	if second[:10] <= first[:10] {
+		t.Fatalf("ULID timestamps not ordered: %s, %s", first, second)
+	}
+}
+
+func TestW3CTraceID_ULID(t *testing.T) {
+
+	now := time.UnixMilli(1714564800000)
+
+	ulid := ULIDGenerator{Now: func() time.Time { return now }}.NewTraceID()
+
+	traceID, ok := W3CTraceID(ulid)
+
+	if !ok {
+		t.Fatalf("ULID %s not converted", ulid)
+	}
+
+	// The 48-bit timestamp leads the hex form
+	if traceID[:12] != fmt.Sprintf("%012x", now.UnixMilli()) {
+		t.Fatalf("Unexpected conversion of %s: %s", ulid, traceID)
+	}
+
+	if _, ok := W3CTraceID("8" + ulid[1:]); ok {
+		t.Fatal("Overflowing ULID accepted")
+	}
+}
+
+func TestTraceProvider_WithGenerator(t *testing.T) {
+
+	provider := NewTraceProvider().WithGenerator(NewDeterministicGenerator(0))
+
+	if id := provider.GetTraceID(context.Background()); id != "00000000000000000000000000000001" {
+		t.Fatal("Unexpected trace ID", id)
+	}
+
+	if _, ok := W3CTraceID(provider.NewTraceID()); !ok {
+		t.Fatal("Deterministic IDs should be W3C-compatible")
+	}
+}
@@ -0,0 +1,238 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	HeaderB3        = "b3"
+	HeaderB3TraceID = "X-B3-TraceId"
+	HeaderB3SpanID  = "X-B3-SpanId"
+	HeaderB3Sampled = "X-B3-Sampled"
+	HeaderB3Flags   = "X-B3-Flags"
+	HeaderRequestID = "X-Request-ID"
+)
+
+const (
+	b3TraceIDPadding  = "0000000000000000"
+	b3SampledAccepted = "1"
+)
+
+// Propagator reads and writes trace context in HTTP headers.
+// Extracted span IDs belong to the caller; the receiver starts its own span.
+type Propagator interface {
+	Extract(header http.Header) (SpanContext, bool)
+	Inject(sc SpanContext, header http.Header)
+}
+
+// W3CPropagator handles traceparent and tracestate
+type W3CPropagator struct{}
+
+func (W3CPropagator) Extract(header http.Header) (SpanContext, bool) {
+
+	sc, err := ParseTraceParent(header.Get(HeaderTraceParent))
+
+	if err != nil {
+		return SpanContext{}, false
+	}
+
+	if state := header.Get(HeaderTraceState); ValidTraceState(state) {
+		sc.TraceState = state
+	}
+
+	return sc, true
+}
+
+func (W3CPropagator) Inject(sc SpanContext, header http.Header) {
+
+	traceParent, ok := FormatTraceParent(sc)
+
+	if !ok {
+		return
+	}
+
+	header.Set(HeaderTraceParent, traceParent)
+
+	if sc.TraceState != "" {
+		header.Set(HeaderTraceState, sc.TraceState)
+	}
+}
+
+// B3Propagator handles Zipkin B3 headers.
+// Extract accepts the single b3 header and the X-B3-* headers; Inject writes
+// the single header when SingleHeader is set and X-B3-* headers otherwise.
+type B3Propagator struct {
+	SingleHeader bool
+}
+
+func (p B3Propagator) Extract(header http.Header) (SpanContext, bool) {
+
+	if single := header.Get(HeaderB3); single != "" {
+		return parseB3Single(single)
+	}
+
+	traceID, ok := b3TraceID(header.Get(HeaderB3TraceID))
+
+	if !ok {
+		return SpanContext{}, false
+	}
+
+	spanID := header.Get(HeaderB3SpanID)
+
+	if !validB3SpanID(spanID) {
+		return SpanContext{}, false
+	}
+
+	sc := SpanContext{TraceID: traceID, SpanID: spanID}
+
+	if header.Get(HeaderB3Sampled) == b3SampledAccepted || header.Get(HeaderB3Flags) == "1" {
+		sc.TraceFlags = TraceFlagSampled
+	}
+
+	return sc, true
+}
+
+func (p B3Propagator) Inject(sc SpanContext, header http.Header) {
+
+	traceID, ok := W3CTraceID(sc.TraceID)
+
+	if !ok || !validB3SpanID(sc.SpanID) {
+		return
+	}
+
+	sampled := "0"
+
+	if sc.TraceFlags&TraceFlagSampled != 0 {
+		sampled = b3SampledAccepted
+	}
+
+	if p.SingleHeader {
+		header.Set(HeaderB3, traceID+"-"+sc.SpanID+"-"+sampled)
+		return
+	}
+
+	header.Set(HeaderB3TraceID, traceID)
+	header.Set(HeaderB3SpanID, sc.SpanID)
+	header.Set(HeaderB3Sampled, sampled)
+}
+
+// parseB3Single parses traceid-spanid[-sampled[-parentspanid]].
+// A sampling-only value such as "0" carries no trace.
+func parseB3Single(value string) (SpanContext, bool) {
+
+	parts := strings.Split(value, "-")
+
+	if len(parts) < 2 || len(parts) > 4 {
+		return SpanContext{}, false
+	}
+
+	traceID, ok := b3TraceID(parts[0])
+
+	if !ok || !validB3SpanID(parts[1]) {
+		return SpanContext{}, false
+	}
+
+	sc := SpanContext{TraceID: traceID, SpanID: parts[1]}
+
+	if len(parts) > 2 && (parts[2] == b3SampledAccepted || parts[2] == "d") {
+		sc.TraceFlags = TraceFlagSampled
+	}
+
+	return sc, true
+}
+
+// b3TraceID accepts 64- or 128-bit IDs, left-padding 64-bit IDs to W3C length
+func b3TraceID(value string) (string, bool) {
+
+	value = strings.ToLower(value)
+
+	if len(value) == 16 {
+		value = b3TraceIDPadding + value
+	}
+
+	if len(value) != 32 || !isLowerHex(value) || isZeroHex(value) {
+		return "", false
+	}
+
+	return value, true
+}
+
+func validB3SpanID(value string) bool {
+	return len(value) == 16 && isLowerHex(value) && !isZeroHex(value)
+}
+
+// RequestIDPropagator carries the trace ID alone in a single header such as X-Request-ID
+type RequestIDPropagator struct {
+	Header string
+}
+
+func NewRequestIDPropagator(header string) RequestIDPropagator {
+	return RequestIDPropagator{Header: header}
+}
+
+func (p RequestIDPropagator) Extract(header http.Header) (SpanContext, bool) {
+
+	traceID := strings.TrimSpace(header.Get(p.Header))
+
+	if traceID == "" {
+		return SpanContext{}, false
+	}
+
+	return SpanContext{TraceID: traceID, TraceFlags: TraceFlagSampled}, true
+}
+
+func (p RequestIDPropagator) Inject(sc SpanContext, header http.Header) {
+
+	if sc.TraceID != "" {
+		header.Set(p.Header, sc.TraceID)
+	}
+}
+
+// CompositePropagator extracts with the first propagator that finds a trace,
+// in order of precedence, and injects with all of them.
+type CompositePropagator []Propagator
+
+func NewCompositePropagator(propagators ...Propagator) CompositePropagator {
+	return CompositePropagator(propagators)
+}
+
+func (c CompositePropagator) Extract(header http.Header) (SpanContext, bool) {
+
+	for _, propagator := range c {
+		if sc, ok := propagator.Extract(header); ok {
+			return sc, true
+		}
+	}
+
+	return SpanContext{}, false
+}
+
+func (c CompositePropagator) Inject(sc SpanContext, header http.Header) {
+
+	for _, propagator := range c {
+		propagator.Inject(sc, header)
+	}
+}
+
+type extractOnly struct {
+	Propagator
+}
+
+func (extractOnly) Inject(SpanContext, http.Header) {}
+
+// ExtractOnly accepts traces from p without writing its headers
+func ExtractOnly(p Propagator) Propagator {
+	return extractOnly{p}
+}
+
+// DefaultPropagator reads traceparent, then X-Trace-ID, then X-Request-ID.
+// It writes traceparent and X-Trace-ID.
+func DefaultPropagator() Propagator {
+
+	return NewCompositePropagator(
+		W3CPropagator{},
+		NewRequestIDPropagator(HeaderTraceID),
+		ExtractOnly(NewRequestIDPropagator(HeaderRequestID)),
+	)
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"net/http"
+	"testing"
+)
+
+func headers(pairs ...string) http.Header {
+
+	header := http.Header{}
+
+	for i := 0; i < len(pairs); i += 2 {
+		header.Set(pairs[i], pairs[i+1])
+	}
+
+	return header
+}
+
+const (
+	testTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testSpanID  = "00f067aa0ba902b7"
+)
+
+func TestB3Propagator_Extract(t *testing.T) {
+
+	cases := map[string]http.Header{
+		"single": headers(HeaderB3, testTraceID+"-"+testSpanID+"-1"),
+		"multi":  headers(HeaderB3TraceID, testTraceID, HeaderB3SpanID, testSpanID, HeaderB3Sampled, "1"),
+	}
+
+	for name, header := range cases {
+
+		sc, ok := B3Propagator{}.Extract(header)
+
+		if !ok || sc.TraceID != testTraceID || sc.SpanID != testSpanID || sc.TraceFlags != TraceFlagSampled {
+			t.Errorf("%s: unexpected span context %+v", name, sc)
+		}
+	}
+
+	sc, ok := B3Propagator{}.Extract(headers(HeaderB3, "a3ce929d0e0e4736-"+testSpanID))
+
+	if !ok || sc.TraceID != "0000000000000000a3ce929d0e0e4736" {
+		t.Fatal("64-bit trace ID not padded", sc)
+	}
+
+	if _, ok := (B3Propagator{}).Extract(headers(HeaderB3, "0")); ok {
+		t.Fatal("Sampling-only header extracted a trace")
+	}
+}
+
+func TestB3Propagator_Inject(t *testing.T) {
+
+	sc := SpanContext{TraceID: testTraceID, SpanID: testSpanID, TraceFlags: TraceFlagSampled}
+
+	header := http.Header{}
+	B3Propagator{SingleHeader: true}.Inject(sc, header)
+
+	if header.Get(HeaderB3) != testTraceID+"-"+testSpanID+"-1" {
+		t.Fatal("Unexpected b3 header", header)
+	}
+
+	header = http.Header{}
+	B3Propagator{}.Inject(sc, header)
+
+	if header.Get(HeaderB3TraceID) != testTraceID || header.Get(HeaderB3SpanID) != testSpanID || header.Get(HeaderB3Sampled) != "1" {
+		t.Fatal("Unexpected X-B3 headers", header)
+	}
+}
+
+func TestCompositePropagator_Precedence(t *testing.T) {
+
+	header := headers(
+		HeaderTraceParent, "00-"+testTraceID+"-"+testSpanID+"-01",
+		HeaderRequestID, "req-1",
+	)
+
+	sc, _ := NewCompositePropagator(W3CPropagator{}, NewRequestIDPropagator(HeaderRequestID)).Extract(header)
+
+	if sc.TraceID != testTraceID {
+		t.Fatal("W3C should win", sc)
+	}
+
+	sc, _ = NewCompositePropagator(NewRequestIDPropagator(HeaderRequestID), W3CPropagator{}).Extract(header)
+
+	if sc.TraceID != "req-1" {
+		t.Fatal("Request ID should win", sc)
+	}
+}
+
+func TestDefaultPropagator_Inject(t *testing.T) {
+
+	header := http.Header{}
+
+	DefaultPropagator().Inject(SpanContext{TraceID: testTraceID, SpanID: testSpanID}, header)
+
+	if header.Get(HeaderTraceParent) == "" || header.Get(HeaderTraceID) != testTraceID {
+		t.Fatal("Missing headers", header)
+	}
+
+	if header.Get(HeaderRequestID) != "" {
+		t.Fatal("X-Request-ID is extract-only", header)
+	}
+}
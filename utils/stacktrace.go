@@ -63,3 +63,42 @@ func isFrameworkFrame(function string) bool {
 
 	return false
 }
+
+// CapturePanic returns the stack of the panicking goroutine starting at the panic site.
+// It must be called from a deferred function while the panic is being recovered.
+func CapturePanic() string {
+
+	const maxDepth = 64
+
+	pcs := make([]uintptr, maxDepth)
+
+	n := runtime.Callers(2, pcs)
+
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var builder strings.Builder
+
+	panicking := false
+
+	for {
+		frame, more := frames.Next()
+
+		if frame.Function == "runtime.gopanic" {
+			panicking = true
+		} else if panicking && !strings.HasPrefix(frame.Function, "runtime.") {
+
+			builder.WriteString(fmt.Sprintf(
+				"%s\n\t%s:%d\n",
+				frame.Function,
+				frame.File,
+				frame.Line,
+			))
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return builder.String()
+}
@@ -1,6 +1,9 @@
 package utils
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestStackTrace(t *testing.T) {
 
@@ -12,3 +15,21 @@ func TestStackTrace(t *testing.T) {
 		t.Fatal("Stacktrace empty")
 	}
 }
+
+func TestCapturePanic(t *testing.T) {
+
+	var trace string
+
+	func() {
+		defer func() {
+			recover()
+			trace = CapturePanic()
+		}()
+
+		panic("boom")
+	}()
+
+	if !strings.HasPrefix(trace, "github.com/krisalay/error-framework/utils.TestCapturePanic.func1") {
+		t.Fatalf("Stack does not start at panic site:\n%s", trace)
+	}
+}
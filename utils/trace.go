@@ -2,25 +2,63 @@ package utils
 
 import (
 	"context"
-
-	"github.com/google/uuid"
+	"net/http"
 )
 
 type contextKey string
 
-const TraceIDKey contextKey = "trace_id"
+const (
+	TraceIDKey     contextKey = "trace_id"
+	SpanContextKey contextKey = "span_context"
+)
+
+// HeaderTraceID carries the trace ID between services using this framework
+const HeaderTraceID = "X-Trace-ID"
 
-type TraceProvider struct{}
+type TraceProvider struct {
+	propagator Propagator
+	generator  IDGenerator
+}
 
 func NewTraceProvider() *TraceProvider {
-	return &TraceProvider{}
+	return &TraceProvider{
+		propagator: DefaultPropagator(),
+		generator:  UUIDv4Generator{},
+	}
+}
+
+// WithGenerator sets how new trace IDs are created
+func (t *TraceProvider) WithGenerator(generator IDGenerator) *TraceProvider {
+	t.generator = generator
+	return t
+}
+
+// WithPropagator sets the header formats read from requests and written to outbound requests
+func (t *TraceProvider) WithPropagator(propagator Propagator) *TraceProvider {
+	t.propagator = propagator
+	return t
+}
+
+// Propagator returns the configured propagator, DefaultPropagator() if none is set
+func (t *TraceProvider) Propagator() Propagator {
+
+	if t.propagator == nil {
+		return DefaultPropagator()
+	}
+
+	return t.propagator
+}
+
+// Extract reads the caller's trace from header
+func (t *TraceProvider) Extract(header http.Header) (SpanContext, bool) {
+	return t.Propagator().Extract(header)
 }
 
 // GetTraceID returns trace id from context or generates new one
 func (t *TraceProvider) GetTraceID(ctx context.Context) string {
 
 	if ctx == nil {
-		return t.generate()
+		return t.NewTraceID()
 	}
 
 	traceID, ok := ctx.Value(TraceIDKey).(string)
@@ -29,14 +67,70 @@ func (t *TraceProvider) GetTraceID(ctx context.Context) string {
 		return traceID
 	}
 
-	return t.generate()
+	return t.NewTraceID()
 }
 
-func (t *TraceProvider) generate() string {
-	return uuid.New().String()
+// GetSpanID returns the span ID of this hop, or "" when none was injected
+func (t *TraceProvider) GetSpanID(ctx context.Context) string {
+
+	if sc, ok := t.SpanContext(ctx); ok {
+		return sc.SpanID
+	}
+
+	return ""
+}
+
+// NewTraceID creates a trace ID with the configured generator, UUIDv4 if none is set
+func (t *TraceProvider) NewTraceID() string {
+
+	if t.generator == nil {
+		return UUIDv4Generator{}.NewTraceID()
+	}
+
+	return t.generator.NewTraceID()
 }
 
 // Inject adds trace id to context
 func (t *TraceProvider) Inject(ctx context.Context, traceID string) context.Context {
 	return context.WithValue(ctx, TraceIDKey, traceID)
 }
+
+// InjectSpanContext adds the trace and span of this hop to context
+func (t *TraceProvider) InjectSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(t.Inject(ctx, sc.TraceID), SpanContextKey, sc)
+}
+
+// SpanContext returns the span context injected into ctx
+func (t *TraceProvider) SpanContext(ctx context.Context) (SpanContext, bool) {
+
+	if ctx == nil {
+		return SpanContext{}, false
+	}
+
+	sc, ok := ctx.Value(SpanContextKey).(SpanContext)
+
+	return sc, ok
+}
+
+// InjectHeaders writes the context's trace to header for an outbound request.
+// Formats that need a span ID, such as traceparent, name this hop's span as the parent.
+func (t *TraceProvider) InjectHeaders(ctx context.Context, header http.Header) {
+
+	if ctx == nil {
+		return
+	}
+
+	sc, ok := t.SpanContext(ctx)
+
+	if !ok {
+		traceID, _ := ctx.Value(TraceIDKey).(string)
+
+		if traceID == "" {
+			return
+		}
+
+		sc = SpanContext{TraceID: traceID}
+	}
+
+	t.Propagator().Inject(sc, header)
+}
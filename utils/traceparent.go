@@ -0,0 +1,214 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	HeaderTraceParent = "traceparent"
+	HeaderTraceState  = "tracestate"
+
+	// TraceFlagSampled is the W3C sampled flag
+	TraceFlagSampled byte = 0x01
+
+	maxTraceStateMembers = 32
+	maxTraceStateLength  = 512
+)
+
+// SpanContext identifies the current hop of a trace
+type SpanContext struct {
+	TraceID    string
+	SpanID     string
+	TraceFlags byte
+	TraceState string // validated W3C tracestate, forwarded unchanged
+}
+
+var errInvalidTraceParent = errors.New("invalid traceparent")
+
+// ParseTraceParent parses a W3C traceparent header.
+// The returned SpanID is the caller's span, i.e. the parent of this hop.
+func ParseTraceParent(value string) (SpanContext, error) {
+
+	value = strings.TrimSpace(value)
+
+	// version-traceid-parentid-flags, later versions may append fields
+	if len(value) < 55 || value[2] != '-' || value[35] != '-' || value[52] != '-' {
+		return SpanContext{}, errInvalidTraceParent
+	}
+
+	version := value[0:2]
+
+	if !isLowerHex(version) || version == "ff" {
+		return SpanContext{}, fmt.Errorf("%w: version %q", errInvalidTraceParent, version)
+	}
+
+	if version == "00" && len(value) != 55 {
+		return SpanContext{}, fmt.Errorf("%w: trailing data", errInvalidTraceParent)
+	}
+
+	if len(value) > 55 && value[55] != '-' {
+		return SpanContext{}, fmt.Errorf("%w: trailing data", errInvalidTraceParent)
+	}
+
+	traceID := value[3:35]
+	parentID := value[36:52]
+	flags := value[53:55]
+
+	if !isLowerHex(traceID) || isZeroHex(traceID) {
+		return SpanContext{}, fmt.Errorf("%w: trace id %q", errInvalidTraceParent, traceID)
+	}
+
+	if !isLowerHex(parentID) || isZeroHex(parentID) {
+		return SpanContext{}, fmt.Errorf("%w: parent id %q", errInvalidTraceParent, parentID)
+	}
+
+	if !isLowerHex(flags) {
+		return SpanContext{}, fmt.Errorf("%w: flags %q", errInvalidTraceParent, flags)
+	}
+
+	decoded, _ := hex.DecodeString(flags)
+
+	return SpanContext{
+		TraceID:    traceID,
+		SpanID:     parentID,
+		TraceFlags: decoded[0],
+	}, nil
+}
+
+// FormatTraceParent renders sc as a version 00 traceparent.
+// It reports false when the trace or span ID cannot be expressed in W3C form.
+func FormatTraceParent(sc SpanContext) (string, bool) {
+
+	traceID, ok := W3CTraceID(sc.TraceID)
+
+	if !ok || len(sc.SpanID) != 16 || !isLowerHex(sc.SpanID) || isZeroHex(sc.SpanID) {
+		return "", false
+	}
+
+	return fmt.Sprintf("00-%s-%s-%02x", traceID, sc.SpanID, sc.TraceFlags), true
+}
+
+// W3CTraceID returns traceID as 32 lowercase hex characters.
+// UUIDs are accepted with or without dashes, and ULIDs are converted to the hex of their bytes.
+func W3CTraceID(traceID string) (string, bool) {
+
+	if id, ok := decodeULID(traceID); ok {
+
+		if id == [16]byte{} {
+			return "", false
+		}
+
+		return hex.EncodeToString(id[:]), true
+	}
+
+	normalized := strings.ToLower(traceID)
+
+	if len(normalized) == 36 && strings.Count(normalized, "-") == 4 {
+		normalized = strings.ReplaceAll(normalized, "-", "")
+	}
+
+	if len(normalized) != 32 || !isLowerHex(normalized) || isZeroHex(normalized) {
+		return "", false
+	}
+
+	return normalized, true
+}
+
+// ValidTraceState reports whether value is a well-formed W3C tracestate
+func ValidTraceState(value string) bool {
+
+	if value == "" || len(value) > maxTraceStateLength {
+		return false
+	}
+
+	members := 0
+
+	for _, member := range strings.Split(value, ",") {
+
+		member = strings.Trim(member, " \t")
+
+		// Empty list members are allowed and ignored
+		if member == "" {
+			continue
+		}
+
+		members++
+
+		key, val, ok := strings.Cut(member, "=")
+
+		if !ok || !validTraceStateKey(key) || !validTraceStateValue(val) {
+			return false
+		}
+	}
+
+	return members > 0 && members <= maxTraceStateMembers
+}
+
+func validTraceStateKey(key string) bool {
+
+	if key == "" || len(key) > 256 {
+		return false
+	}
+
+	for i := 0; i < len(key); i++ {
+
+		c := key[i]
+
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		case i > 0 && (c == '_' || c == '-' || c == '*' || c == '/' || c == '@'):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func validTraceStateValue(value string) bool {
+
+	if value == "" || len(value) > 256 || value[len(value)-1] == ' ' {
+		return false
+	}
+
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c < 0x20 || c > 0x7e || c == ',' || c == '=' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewSpanID returns a random 8-byte span ID in hex
+func NewSpanID() string {
+
+	var id [8]byte
+
+	for {
+		rand.Read(id[:])
+
+		if id != [8]byte{} {
+			return hex.EncodeToString(id[:])
+		}
+	}
+}
+
+func isLowerHex(value string) bool {
+
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isZeroHex(value string) bool {
+	return strings.Trim(value, "0") == ""
+}
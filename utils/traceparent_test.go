@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestParseTraceParent(t *testing.T) {
+
+	sc, err := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sc.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || sc.SpanID != "00f067aa0ba902b7" || sc.TraceFlags != TraceFlagSampled {
+		t.Fatalf("Unexpected span context %+v", sc)
+	}
+
+	invalid := []string{
+		"",
+		"4bf92f3577b34da6a3ce929d0e0e4736",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	}
+
+	for _, value := range invalid {
+		if _, err := ParseTraceParent(value); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+
+	// Future versions may append fields
+	if _, err := ParseTraceParent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFormatTraceParent(t *testing.T) {
+
+	traceParent, ok := FormatTraceParent(SpanContext{
+		TraceID:    "4BF92F35-77B3-4DA6-A3CE-929D0E0E4736",
+		SpanID:     "00f067aa0ba902b7",
+		TraceFlags: TraceFlagSampled,
+	})
+
+	if !ok || traceParent != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Fatal("Unexpected traceparent", traceParent)
+	}
+
+	if _, ok := FormatTraceParent(SpanContext{TraceID: "req-123", SpanID: NewSpanID()}); ok {
+		t.Fatal("Non-W3C trace ID formatted")
+	}
+}
+
+func TestValidTraceState(t *testing.T) {
+
+	cases := map[string]bool{
+		"rojo=00f067aa0ba902b7,congo=t61rcWkgMzE": true,
+		"tenant@vendor=abc, ,other=1":             true,
+		"":                                        false,
+		"novalue":                                 false,
+		"UPPER=1":                                 false,
+		"key=a=b":                                 false,
+	}
+
+	for value, expected := range cases {
+		if ValidTraceState(value) != expected {
+			t.Errorf("%q: expected %v", value, expected)
+		}
+	}
+}
+
+func TestTraceProvider_InjectHeaders(t *testing.T) {
+
+	provider := NewTraceProvider()
+
+	ctx := provider.InjectSpanContext(context.Background(), SpanContext{
+		TraceID:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:     "00f067aa0ba902b7",
+		TraceFlags: TraceFlagSampled,
+		TraceState: "rojo=1",
+	})
+
+	if provider.GetTraceID(ctx) != "4bf92f3577b34da6a3ce929d0e0e4736" || provider.GetSpanID(ctx) != "00f067aa0ba902b7" {
+		t.Fatal("Span context not stored")
+	}
+
+	header := http.Header{}
+	provider.InjectHeaders(ctx, header)
+
+	if header.Get(HeaderTraceParent) != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Fatal("Unexpected traceparent", header.Get(HeaderTraceParent))
+	}
+
+	if header.Get(HeaderTraceState) != "rojo=1" || header.Get(HeaderTraceID) == "" {
+		t.Fatal("Unexpected headers", header)
+	}
+}